@@ -0,0 +1,350 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/timohirt/terraform-provider-hetznerdns/hetznerdns/api/fake"
+)
+
+// Cassettes are recorded by running the tests with -record. The requests are
+// sent to the API configured by HETZNER_DNS_API_TOKEN and
+// HETZNER_DNS_API_ENDPOINT, or to the fake API if no token is set. Otherwise
+// the recorded responses are replayed and no request leaves the process.
+//
+// Only cassettes recorded against the real API check the client against it.
+// Every cassette states which API it was recorded against.
+var recordCassettes = flag.Bool("record", false, "record the cassettes in testdata/cassettes")
+
+const (
+	cassetteDir   = "testdata/cassettes"
+	redactedToken = "REDACTED"
+	// recordedAgainstFake marks cassettes recorded against the fake API
+	recordedAgainstFake = "fake"
+)
+
+// cassette contains the interactions of one test with the API
+type cassette struct {
+	// RecordedAgainst is the endpoint of the API the cassette was recorded
+	// against, or recordedAgainstFake
+	RecordedAgainst string        `json:"recorded_against"`
+	Interactions    []interaction `json:"interactions"`
+}
+
+type interaction struct {
+	Request  recordedRequest  `json:"request"`
+	Response recordedResponse `json:"response"`
+}
+
+// recordedRequest is matched against the requests during replay. The path
+// is relative to the API endpoint, so cassettes don't depend on it.
+type recordedRequest struct {
+	Method string `json:"method"`
+	Path   string `json:"path"`
+	Query  string `json:"query,omitempty"`
+	Body   string `json:"body,omitempty"`
+}
+
+type recordedResponse struct {
+	StatusCode int               `json:"status_code"`
+	Header     map[string]string `json:"header,omitempty"`
+	Body       string            `json:"body,omitempty"`
+}
+
+// recordedHeaders are the response headers kept in cassettes
+var recordedHeaders = []string{"Content-Type", "Retry-After"}
+
+// createCassetteClient returns a Client which replays the cassette of the
+// test or, with -record, records it. The cassette is plugged in as the
+// transport of the client, so the retry and scheduling logic is used as well.
+func createCassetteClient(t *testing.T) *Client {
+	path := filepath.Join(cassetteDir, strings.ReplaceAll(t.Name(), "/", "_")+".json")
+
+	if *recordCassettes {
+		return createRecordingClient(t, path)
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatalf("cassette %s not found, record it with -record: %s", path, err)
+	}
+	var c cassette
+	if err := json.Unmarshal(data, &c); err != nil {
+		t.Fatalf("cassette %s is invalid: %s", path, err)
+	}
+	if c.RecordedAgainst == recordedAgainstFake {
+		t.Logf("cassette %s was recorded against the fake API, record it with a real API token to check the client against the real API", path)
+	}
+
+	player := &cassettePlayer{endpoint: DefaultAPIEndpoint, cassette: c}
+	t.Cleanup(func() {
+		for _, unused := range player.unusedInteractions() {
+			t.Errorf("recorded request was not sent: %s %s?%s %s", unused.Method, unused.Path, unused.Query, unused.Body)
+		}
+	})
+
+	scheduler := newRequestScheduler()
+	return &Client{
+		scheduler:   scheduler,
+		apiToken:    "irrelevant",
+		apiEndpoint: DefaultAPIEndpoint,
+		httpClient:  newHTTPClient(player, scheduler, defaultRetryConfig()),
+	}
+}
+
+func createRecordingClient(t *testing.T, path string) *Client {
+	apiToken := os.Getenv("HETZNER_DNS_API_TOKEN")
+	endpoint := os.Getenv("HETZNER_DNS_API_ENDPOINT")
+	recordedAgainst := endpoint
+	if recordedAgainst == "" {
+		recordedAgainst = DefaultAPIEndpoint
+	}
+	if apiToken == "" {
+		server := fake.NewServer("fake-api-token")
+		t.Cleanup(server.Close)
+		apiToken = "fake-api-token"
+		endpoint = server.URL()
+		recordedAgainst = recordedAgainstFake
+	}
+
+	client, diags := NewClient(apiToken, ClientOpts{Endpoint: endpoint})
+	if diags.HasError() {
+		t.Fatalf("err: %v", diags)
+	}
+
+	recorder := &cassetteRecorder{endpoint: client.apiEndpoint, apiToken: apiToken, next: newDefaultTransport()}
+	recorder.cassette.RecordedAgainst = recordedAgainst
+	client.httpClient = newHTTPClient(recorder, client.scheduler, defaultRetryConfig())
+
+	t.Cleanup(func() {
+		if t.Failed() {
+			return
+		}
+		data, err := json.MarshalIndent(recorder.cassette, "", "  ")
+		if err == nil {
+			err = os.MkdirAll(cassetteDir, 0755)
+		}
+		if err == nil {
+			err = ioutil.WriteFile(path, append(data, '\n'), 0644)
+		}
+		if err != nil {
+			t.Fatalf("failed to write cassette %s: %s", path, err)
+		}
+	})
+	return client
+}
+
+// newRecordedRequest returns the parts of a request which are matched during
+// replay. JSON bodies are normalized, so the order of keys doesn't matter.
+func newRecordedRequest(endpoint string, req *http.Request, body []byte) recordedRequest {
+	path := req.URL.Path
+	if endpointURL, err := url.Parse(endpoint); err == nil {
+		path = strings.TrimPrefix(path, endpointURL.Path)
+	}
+
+	return recordedRequest{
+		Method: req.Method,
+		Path:   path,
+		Query:  req.URL.Query().Encode(),
+		Body:   normalizeBody(body),
+	}
+}
+
+func normalizeBody(body []byte) string {
+	var v interface{}
+	if err := json.Unmarshal(body, &v); err == nil {
+		if normalized, err := json.Marshal(v); err == nil {
+			return string(normalized)
+		}
+	}
+	return string(body)
+}
+
+func readRequestBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil {
+		return nil, nil
+	}
+	body, err := ioutil.ReadAll(req.Body)
+	req.Body.Close()
+	return body, err
+}
+
+// cassetteRecorder sends requests to the API and records the interactions
+// with the API token redacted.
+type cassetteRecorder struct {
+	endpoint string
+	apiToken string
+	next     http.RoundTripper
+
+	mu       sync.Mutex
+	cassette cassette
+}
+
+func (r *cassetteRecorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := readRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+	req.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	resp, err := r.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	respBody, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = ioutil.NopCloser(bytes.NewReader(respBody))
+
+	recorded := interaction{
+		Request: newRecordedRequest(r.endpoint, req, body),
+		Response: recordedResponse{
+			StatusCode: resp.StatusCode,
+			Header:     map[string]string{},
+			Body:       string(respBody),
+		},
+	}
+	for _, name := range recordedHeaders {
+		if value := resp.Header.Get(name); value != "" {
+			recorded.Response.Header[name] = value
+		}
+	}
+	recorded.Request.Body = strings.ReplaceAll(recorded.Request.Body, r.apiToken, redactedToken)
+	recorded.Response.Body = strings.ReplaceAll(recorded.Response.Body, r.apiToken, redactedToken)
+
+	r.mu.Lock()
+	r.cassette.Interactions = append(r.cassette.Interactions, recorded)
+	r.mu.Unlock()
+	return resp, nil
+}
+
+// cassettePlayer responds to requests with the first unused interaction of a
+// cassette which matches the method, path, query and body of the request.
+type cassettePlayer struct {
+	endpoint string
+
+	mu       sync.Mutex
+	cassette cassette
+	used     []bool
+}
+
+func (p *cassettePlayer) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := readRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+	actual := newRecordedRequest(p.endpoint, req, body)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.used == nil {
+		p.used = make([]bool, len(p.cassette.Interactions))
+	}
+
+	for i, recorded := range p.cassette.Interactions {
+		if p.used[i] || recorded.Request != actual {
+			continue
+		}
+		p.used[i] = true
+
+		header := http.Header{}
+		for name, value := range recorded.Response.Header {
+			header.Set(name, value)
+		}
+		return &http.Response{
+			Status:     fmt.Sprintf("%d %s", recorded.Response.StatusCode, http.StatusText(recorded.Response.StatusCode)),
+			StatusCode: recorded.Response.StatusCode,
+			Header:     header,
+			Body:       ioutil.NopCloser(strings.NewReader(recorded.Response.Body)),
+			Request:    req,
+		}, nil
+	}
+
+	// HTTP 501 is not retried, so an unexpected request fails the test at once
+	message, _ := json.Marshal(map[string]string{
+		"message": fmt.Sprintf("no recorded interaction matches %s %s?%s %s", actual.Method, actual.Path, actual.Query, actual.Body),
+	})
+	return &http.Response{
+		Status:     "501 Not Implemented",
+		StatusCode: http.StatusNotImplemented,
+		Header:     http.Header{"Content-Type": []string{jsonContentType}},
+		Body:       ioutil.NopCloser(bytes.NewReader(message)),
+		Request:    req,
+	}, nil
+}
+
+func (p *cassettePlayer) unusedInteractions() []recordedRequest {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var unused []recordedRequest
+	for i, recorded := range p.cassette.Interactions {
+		if p.used == nil || !p.used[i] {
+			unused = append(unused, recorded.Request)
+		}
+	}
+	return unused
+}
+
+func TestCassettePlayerMatchesMethodPathQueryAndBody(t *testing.T) {
+	player := &cassettePlayer{endpoint: DefaultAPIEndpoint, cassette: cassette{Interactions: []interaction{
+		{
+			Request:  recordedRequest{Method: http.MethodPost, Path: "/zones", Body: `{"name":"zone1.online","ttl":60}`},
+			Response: recordedResponse{StatusCode: http.StatusOK, Body: `{"zone":{"id":"1"}}`},
+		},
+		{
+			Request:  recordedRequest{Method: http.MethodGet, Path: "/records", Query: "page=1&per_page=100&zone_id=1"},
+			Response: recordedResponse{StatusCode: http.StatusOK, Body: `{"records":[]}`},
+		},
+	}}}
+
+	send := func(method string, path string, body string) int {
+		req, _ := http.NewRequest(method, DefaultAPIEndpoint+path, strings.NewReader(body))
+		resp, err := player.RoundTrip(req)
+		assert.NoError(t, err)
+		return resp.StatusCode
+	}
+
+	assert.Equal(t, http.StatusNotImplemented, send(http.MethodPost, "/zones", `{"name":"zone2.online","ttl":60}`))
+	assert.Equal(t, http.StatusNotImplemented, send(http.MethodPut, "/zones", `{"name":"zone1.online","ttl":60}`))
+	assert.Equal(t, http.StatusNotImplemented, send(http.MethodGet, "/records?zone_id=2&page=1&per_page=100", ""))
+	assert.Equal(t, http.StatusNotImplemented, send(http.MethodGet, "/records/1", ""))
+	assert.Len(t, player.unusedInteractions(), 2)
+
+	// JSON bodies match regardless of formatting and the order of keys
+	assert.Equal(t, http.StatusOK, send(http.MethodPost, "/zones", `{"ttl": 60, "name": "zone1.online"}`))
+	assert.Equal(t, http.StatusOK, send(http.MethodGet, "/records?zone_id=1&page=1&per_page=100", ""))
+	assert.Empty(t, player.unusedInteractions())
+
+	// Every interaction is replayed once
+	assert.Equal(t, http.StatusNotImplemented, send(http.MethodPost, "/zones", `{"name":"zone1.online","ttl":60}`))
+}
+
+func TestCassetteRecorderRedactsAPIToken(t *testing.T) {
+	server := fake.NewServer("secret-token")
+	defer server.Close()
+	client, _ := NewClient("secret-token", ClientOpts{Endpoint: server.URL()})
+	recorder := &cassetteRecorder{endpoint: client.apiEndpoint, apiToken: "secret-token", next: newDefaultTransport()}
+	client.httpClient = newHTTPClient(recorder, client.scheduler, defaultRetryConfig())
+
+	_, err := client.ValidateZoneFile(context.Background(), "www IN TXT secret-token\n")
+	assert.NoError(t, err)
+
+	data, _ := json.Marshal(recorder.cassette)
+	assert.NotContains(t, string(data), "secret-token")
+	assert.Equal(t, "/zones/file/validate", recorder.cassette.Interactions[0].Request.Path)
+}
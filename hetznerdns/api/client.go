@@ -4,160 +4,325 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
-	"log"
 	"net/http"
+	"net/url"
+	"strconv"
 	"strings"
-	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 
 	"github.com/hashicorp/go-retryablehttp"
 )
 
-// UnauthorizedError represents the message of a HTTP 401 response
-type UnauthorizedError ErrorMessage
+const (
+	jsonContentType = "application/json; charset=utf-8"
+	textContentType = "text/plain; charset=utf-8"
+)
 
-// UnprocessableEntityError represents the generic structure of a error response
-type UnprocessableEntityError struct {
-	Error ErrorMessage `json:"error"`
+// retryConfig configures how often failed requests are retried and how long
+// to wait between attempts. A Retry-After header of the API takes precedence
+// over the wait times.
+type retryConfig struct {
+	maxRetries int
+	waitMin    time.Duration
+	waitMax    time.Duration
 }
 
-// ErrorMessage is the message of an error response
-type ErrorMessage struct {
-	Message string `json:"message"`
+// Defaults of the retry policy of a Client
+const (
+	DefaultMaxRetries   = 10
+	DefaultRetryWaitMin = 1 * time.Second
+	DefaultRetryWaitMax = 30 * time.Second
+)
+
+// maxConflictRetries is how often a write rejected with HTTP 422 is retried.
+// The API rejects concurrent writes to a zone with HTTP 422, see issue #5.
+// lockWrites prevents them between the requests of a Client, but not with
+// other clients writing to the same zone. The response can't be told apart
+// from an invalid request, so invalid requests are retried as well, but only
+// a few times to let them fail within seconds.
+const maxConflictRetries = 2
+
+type conflictRetriesKey struct{}
+
+// withConflictRetries returns a context which counts the retries of a write
+// rejected with HTTP 422.
+func withConflictRetries(ctx context.Context) context.Context {
+	if retries, ok := ctx.Value(conflictRetriesKey{}).(*int32); ok && retries == nil {
+		// The request was sent withoutConflictRetries
+		return ctx
+	}
+	return context.WithValue(ctx, conflictRetriesKey{}, new(int32))
 }
 
-type createHTTPClient func() *http.Client
+// withoutConflictRetries returns a context for a request which isn't a write,
+// even though it's sent with POST.
+func withoutConflictRetries(ctx context.Context) context.Context {
+	return context.WithValue(ctx, conflictRetriesKey{}, (*int32)(nil))
+}
 
-func defaultCreateHTTPClient() *http.Client {
+// retryConflict reports whether resp may be the rejection of a concurrent
+// write which is retried.
+func retryConflict(ctx context.Context, resp *http.Response) bool {
+	if resp.StatusCode != http.StatusUnprocessableEntity || resp.Request == nil || resp.Request.Method == http.MethodGet {
+		return false
+	}
+	retries, _ := ctx.Value(conflictRetriesKey{}).(*int32)
+	return retries != nil && atomic.AddInt32(retries, 1) <= maxConflictRetries
+}
+
+func defaultRetryConfig() retryConfig {
+	return retryConfig{
+		maxRetries: DefaultMaxRetries,
+		waitMin:    DefaultRetryWaitMin,
+		waitMax:    DefaultRetryWaitMax,
+	}
+}
+
+// newHTTPClient creates the retrying HTTP client used for all requests of a
+// Client. Every attempt is sent through transport once the scheduler allows it.
+func newHTTPClient(transport http.RoundTripper, scheduler *requestScheduler, retry retryConfig) *http.Client {
 	retryableClient := retryablehttp.NewClient()
+	retryableClient.HTTPClient = &http.Client{
+		Transport: &scheduledTransport{
+			scheduler: scheduler,
+			next:      &loggingTransport{next: transport},
+		},
+	}
+	// Requests are logged by the loggingTransport instead
+	retryableClient.Logger = nil
 	retryableClient.CheckRetry = func(ctx context.Context, resp *http.Response, err error) (bool, error) {
+		// Never retry once the context is canceled or its deadline exceeded
+		if ctx.Err() != nil {
+			return false, ctx.Err()
+		}
+
 		ok, err := retryablehttp.DefaultRetryPolicy(ctx, resp, err)
-		if !ok && resp.StatusCode == http.StatusUnprocessableEntity {
-			return true, nil
+		if !ok && err == nil && resp != nil && retryConflict(ctx, resp) {
+			ok = true
+		}
+		if guard := retryGuardFrom(ctx); ok && guard != nil {
+			guard.attemptFailed(resp)
 		}
 		return ok, err
 	}
-	retryableClient.RetryMax = 10
+	retryableClient.PrepareRetry = prepareRetry
+	retryableClient.RetryMax = retry.maxRetries
+	retryableClient.RetryWaitMin = retry.waitMin
+	retryableClient.RetryWaitMax = retry.waitMax
+	// Return the last response once retries are exhausted, so that it
+	// is turned into a typed error instead of a generic "giving up" error.
+	retryableClient.ErrorHandler = retryablehttp.PassthroughErrorHandler
 	return retryableClient.StandardClient()
 }
 
+// DefaultAPIEndpoint is the base URL of the public Hetzner DNS API.
+const DefaultAPIEndpoint = "https://dns.hetzner.com/api/v1"
+
+// DefaultUserAgent is the User-Agent header sent by a Client unless
+// ClientOpts.UserAgent is set.
+const DefaultUserAgent = "terraform-provider-hetznerdns"
+
 // Client for the Hetzner DNS API.
 type Client struct {
-	requestLock      sync.Mutex
-	apiToken         string
-	createHTTPClient createHTTPClient
+	scheduler   *requestScheduler
+	apiToken    string
+	apiEndpoint string
+	userAgent   string
+	httpClient  *http.Client
 }
 
-// NewClient creates a new API Client using a given api token.
-func NewClient(apiToken string) (*Client, diag.Diagnostics) {
-	return &Client{apiToken: apiToken, createHTTPClient: defaultCreateHTTPClient}, nil
+// ClientOpts configures a Client. The zero value of a field selects its default.
+type ClientOpts struct {
+	// Endpoint is the base URL of the API. Defaults to DefaultAPIEndpoint.
+	Endpoint string
+	// MaxRetries is how often a failed request is retried. Defaults to
+	// DefaultMaxRetries. Set it to 0 to never retry.
+	MaxRetries *int
+	// RetryWaitMin is the minimum time to wait before a retry. Defaults to
+	// DefaultRetryWaitMin, or to RetryWaitMax if that is lower.
+	RetryWaitMin *time.Duration
+	// RetryWaitMax is the maximum time to wait before a retry. Defaults to
+	// DefaultRetryWaitMax, or to RetryWaitMin if that is higher.
+	RetryWaitMax *time.Duration
+	// RequestTimeout limits the time of every attempt to send a request,
+	// including reading the response. Defaults to no limit.
+	RequestTimeout time.Duration
+	// UserAgent is the User-Agent header sent with every request. Defaults
+	// to DefaultUserAgent.
+	UserAgent string
+	// ProxyURL is the URL of a HTTP proxy requests are sent through. It may
+	// contain credentials. Defaults to the proxy configured by the
+	// HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment variables.
+	ProxyURL string
+	// CACertPEM contains PEM encoded CA certificates which are trusted in
+	// addition to the system CAs, e.g. the CA of a TLS intercepting proxy.
+	CACertPEM string
+	// InsecureSkipVerify disables the verification of the TLS certificate
+	// of the API. Don't use it outside of test environments.
+	InsecureSkipVerify bool
 }
 
-func (c *Client) doHTTPRequest(apiToken string, method string, url string, body io.Reader) (*http.Response, error) {
-	client := c.createHTTPClient()
+// NewClient creates a new API Client using a given api token and options.
+func NewClient(apiToken string, opts ClientOpts) (*Client, diag.Diagnostics) {
+	apiEndpoint := opts.Endpoint
+	if apiEndpoint == "" {
+		apiEndpoint = DefaultAPIEndpoint
+	}
 
-	log.Printf("[DEBUG] HTTP request to API %s %s", method, url)
-	req, err := http.NewRequest(method, url, body)
-	if err != nil {
-		return nil, err
+	endpointURL, err := url.Parse(apiEndpoint)
+	if err != nil || endpointURL.Scheme == "" || endpointURL.Host == "" {
+		return nil, diag.Errorf("API endpoint '%s' is not a valid absolute URL", apiEndpoint)
 	}
 
-	req.Header.Add("Auth-API-Token", apiToken)
-	req.Header.Add("Accept", "application/json; charset=utf-8")
-	if body != nil {
-		req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	retry := defaultRetryConfig()
+	if opts.MaxRetries != nil {
+		retry.maxRetries = *opts.MaxRetries
+	}
+	if opts.RetryWaitMin != nil {
+		retry.waitMin = *opts.RetryWaitMin
+	}
+	if opts.RetryWaitMax != nil {
+		retry.waitMax = *opts.RetryWaitMax
+	}
+	// A default wait gives way to an explicit wait it contradicts, e.g. a
+	// maximum wait below the default minimum wait.
+	if opts.RetryWaitMin == nil && retry.waitMin > retry.waitMax {
+		retry.waitMin = retry.waitMax
+	}
+	if opts.RetryWaitMax == nil && retry.waitMax < retry.waitMin {
+		retry.waitMax = retry.waitMin
+	}
+	if retry.maxRetries < 0 {
+		return nil, diag.Errorf("Maximum number of retries must not be negative, got %d", retry.maxRetries)
+	}
+	if retry.waitMin < 0 || retry.waitMin > retry.waitMax {
+		return nil, diag.Errorf("Minimum retry wait time %s must be between 0 and the maximum retry wait time %s", retry.waitMin, retry.waitMax)
+	}
+	if opts.RequestTimeout < 0 {
+		return nil, diag.Errorf("Request timeout must not be negative, got %s", opts.RequestTimeout)
 	}
 
-	resp, err := client.Do(req)
+	defaultTransport := newDefaultTransport()
+	if err := configureConnection(defaultTransport, opts); err != nil {
+		return nil, diag.FromErr(err)
+	}
+
+	var transport http.RoundTripper = defaultTransport
+	if opts.RequestTimeout > 0 {
+		transport = &timeoutTransport{timeout: opts.RequestTimeout, next: transport}
+	}
+
+	userAgent := opts.UserAgent
+	if userAgent == "" {
+		userAgent = DefaultUserAgent
+	}
+
+	scheduler := newRequestScheduler()
+	return &Client{
+		scheduler:   scheduler,
+		apiToken:    apiToken,
+		apiEndpoint: strings.TrimSuffix(apiEndpoint, "/"),
+		userAgent:   userAgent,
+		httpClient:  newHTTPClient(transport, scheduler, retry),
+	}, nil
+}
 
+func (c *Client) doHTTPRequest(ctx context.Context, method string, requestURL string, body io.Reader) (*http.Response, error) {
+	req, err := c.newRequest(ctx, method, requestURL, body)
 	if err != nil {
 		return nil, err
 	}
+	return c.doRequest(req)
+}
 
-	if resp.StatusCode == http.StatusUnauthorized {
-		unauthorizedError, err := parseUnauthorizedError(resp)
-		if err != nil {
-			return nil, err
-		}
-		return nil, fmt.Errorf("API returned HTTP 401 Unauthorized error with message: '%s'. Double check your API key is still valid", unauthorizedError.Message)
+// newRequest creates a request to the API which sends and accepts JSON.
+func (c *Client) newRequest(ctx context.Context, method string, requestURL string, body io.Reader) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(withLogSubsystem(withAttemptCounter(withConflictRetries(ctx))), method, requestURL, body)
+	if err != nil {
+		return nil, err
+	}
 
-	} else if resp.StatusCode == http.StatusUnprocessableEntity {
-		unprocessableEntityError, err := parseUnprocessableEntityError(resp)
+	req.Header.Add("Auth-API-Token", c.apiToken)
+	req.Header.Add("Accept", jsonContentType)
+	if c.userAgent != "" {
+		req.Header.Set("User-Agent", c.userAgent)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", jsonContentType)
+	}
+	return req, nil
+}
+
+// doRequest sends req and returns an error for responses with a HTTP
+// status code >= 400. Write requests are sent one at a time.
+func (c *Client) doRequest(req *http.Request) (*http.Response, error) {
+	if req.Method != http.MethodGet {
+		unlock, err := c.scheduler.lockWrites(req.Context())
 		if err != nil {
 			return nil, err
 		}
-
-		return nil, fmt.Errorf("API returned HTTP 422 Unprocessable Entity error with message: '%s'", unprocessableEntityError.Error.Message)
+		defer unlock()
 	}
-	return resp, nil
-}
 
-func parseUnprocessableEntityError(resp *http.Response) (*UnprocessableEntityError, error) {
-	body, err := ioutil.ReadAll(resp.Body)
-	defer resp.Body.Close()
+	resp, err := c.httpClient.Do(req)
 
-	if err != nil {
-		return nil, fmt.Errorf("Error reading HTTP response body: %e", err)
-	}
-	var unprocessableEntityError UnprocessableEntityError
-	err = parseJSON(body, &unprocessableEntityError)
 	if err != nil {
 		return nil, err
 	}
-	return &unprocessableEntityError, nil
-}
 
-func parseUnauthorizedError(resp *http.Response) (*UnauthorizedError, error) {
-	var unauthorizedError UnauthorizedError
-	err := readAndParseJSONBody(resp, &unauthorizedError)
-	if err != nil {
-		return nil, err
+	if resp.StatusCode >= http.StatusBadRequest {
+		return nil, newErrorFromResponse(req, resp)
 	}
-	return &unauthorizedError, nil
+	return resp, nil
 }
 
-func (c *Client) doGetRequest(url string) (*http.Response, error) {
-	return c.doHTTPRequest(c.apiToken, http.MethodGet, url, nil)
+func (c *Client) doGetRequest(ctx context.Context, requestURL string) (*http.Response, error) {
+	return c.doHTTPRequest(ctx, http.MethodGet, requestURL, nil)
 }
 
-func (c *Client) doDeleteRequest(url string) (*http.Response, error) {
-	return c.doHTTPRequest(c.apiToken, http.MethodDelete, url, nil)
+// doDeleteRequest deletes the object at requestURL. A HTTP 404 after a retry
+// means an earlier attempt deleted the object and is no error.
+func (c *Client) doDeleteRequest(ctx context.Context, requestURL string) error {
+	ctx, guard := withRetryGuard(ctx, nil)
+	resp, err := c.doHTTPRequest(ctx, http.MethodDelete, requestURL, nil)
+	if errors.Is(err, ErrNotFound) && guard.retried {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("HTTP status %d unhandled", resp.StatusCode)
+	}
+	return nil
 }
 
-func (c *Client) doPostRequest(url string, bodyJSON interface{}) (*http.Response, error) {
+func (c *Client) doPostRequest(ctx context.Context, requestURL string, bodyJSON interface{}) (*http.Response, error) {
 	reqJSON, err := json.Marshal(bodyJSON)
 	if err != nil {
-		return nil, fmt.Errorf("Error serializing JSON body %s", err)
+		return nil, fmt.Errorf("Error serializing JSON body %w", err)
 	}
 	body := bytes.NewReader(reqJSON)
 
-	// This lock ensures that only one Post request is sent to Hetzber API
-	// at a time. See issue #5 for context.
-	c.requestLock.Lock()
-	response, err := c.doHTTPRequest(c.apiToken, http.MethodPost, url, body)
-	c.requestLock.Unlock()
-
-	return response, err
+	return c.doHTTPRequest(ctx, http.MethodPost, requestURL, body)
 }
 
-func (c *Client) doPutRequest(url string, bodyJSON interface{}) (*http.Response, error) {
+func (c *Client) doPutRequest(ctx context.Context, requestURL string, bodyJSON interface{}) (*http.Response, error) {
 	reqJSON, err := json.Marshal(bodyJSON)
 	if err != nil {
-		return nil, fmt.Errorf("Error serializing JSON body %s", err)
+		return nil, fmt.Errorf("Error serializing JSON body %w", err)
 	}
 	body := bytes.NewReader(reqJSON)
 
-	// This lock ensures that only one Post request is sent to Hetzber API
-	// at a time. See issue #5 for context.
-	c.requestLock.Lock()
-	response, err := c.doHTTPRequest(c.apiToken, http.MethodPut, url, body)
-	c.requestLock.Unlock()
-
-	return response, err
+	return c.doHTTPRequest(ctx, http.MethodPut, requestURL, body)
 }
 
 func readAndParseJSONBody(resp *http.Response, respType interface{}) error {
@@ -165,7 +330,7 @@ func readAndParseJSONBody(resp *http.Response, respType interface{}) error {
 	defer resp.Body.Close()
 
 	if err != nil {
-		return fmt.Errorf("Error reading HTTP response body %s", err)
+		return fmt.Errorf("Error reading HTTP response body %w", err)
 	}
 
 	return parseJSON(body, respType)
@@ -176,10 +341,10 @@ func parseJSON(data []byte, respType interface{}) error {
 }
 
 // GetZone reads the current state of a DNS zone
-func (c *Client) GetZone(id string) (*Zone, error) {
-	resp, err := c.doGetRequest(fmt.Sprintf("https://dns.hetzner.com/api/v1/zones/%s", id))
+func (c *Client) GetZone(ctx context.Context, id string) (*Zone, error) {
+	resp, err := c.doGetRequest(ctx, fmt.Sprintf("%s/zones/%s", c.apiEndpoint, id))
 	if err != nil {
-		return nil, fmt.Errorf("Error getting zone %s: %s", id, err)
+		return nil, fmt.Errorf("Error getting zone %s: %w", id, err)
 	}
 
 	if resp.StatusCode == http.StatusOK {
@@ -189,18 +354,17 @@ func (c *Client) GetZone(id string) (*Zone, error) {
 			return nil, err
 		}
 		return &response.Zone, nil
-	} else if resp.StatusCode == http.StatusNotFound {
-		return nil, nil
 	}
 
 	return nil, fmt.Errorf("Error getting Zone. HTTP status %d unhandled", resp.StatusCode)
 }
 
 // UpdateZone takes the passed state and updates the respective Zone
-func (c *Client) UpdateZone(zone Zone) (*Zone, error) {
-	resp, err := c.doPutRequest(fmt.Sprintf("https://dns.hetzner.com/api/v1/zones/%s", zone.ID), zone)
+func (c *Client) UpdateZone(ctx context.Context, zone Zone) (*Zone, error) {
+	reqBody := UpdateZoneRequest{ID: zone.ID, Name: zone.Name, TTL: zone.TTL}
+	resp, err := c.doPutRequest(ctx, fmt.Sprintf("%s/zones/%s", c.apiEndpoint, zone.ID), reqBody)
 	if err != nil {
-		return nil, fmt.Errorf("Error updating zone %s: %s", zone.ID, err)
+		return nil, fmt.Errorf("Error updating zone %s: %w", zone.ID, err)
 	}
 
 	if resp.StatusCode == http.StatusOK {
@@ -216,42 +380,80 @@ func (c *Client) UpdateZone(zone Zone) (*Zone, error) {
 }
 
 // DeleteZone deletes a given DNS zone
-func (c *Client) DeleteZone(id string) error {
-	resp, err := c.doDeleteRequest(fmt.Sprintf("https://dns.hetzner.com/api/v1/zones/%s", id))
+func (c *Client) DeleteZone(ctx context.Context, id string) error {
+	err := c.doDeleteRequest(ctx, fmt.Sprintf("%s/zones/%s", c.apiEndpoint, id))
 	if err != nil {
-		return fmt.Errorf("Error deleting zone %s: %s", id, err)
+		return fmt.Errorf("Error deleting zone %s: %w", id, err)
 	}
-
-	if resp.StatusCode == http.StatusOK {
-		return nil
-	}
-	return fmt.Errorf("Error deleting Zone. HTTP status %d unhandled", resp.StatusCode)
+	return nil
 }
 
 // GetZoneByName reads the current state of a DNS zone with a given name
-func (c *Client) GetZoneByName(name string) (*Zone, error) {
-	resp, err := c.doGetRequest(fmt.Sprintf("https://dns.hetzner.com/api/v1/zones?name=%s", name))
+func (c *Client) GetZoneByName(ctx context.Context, name string) (*Zone, error) {
+	zones, err := c.ListZones(ctx, ListZonesOpts{Name: name})
 	if err != nil {
-		return nil, fmt.Errorf("Error getting zone %s: %s", name, err)
+		return nil, fmt.Errorf("Error getting zone %s: %w", name, err)
 	}
 
-	if resp.StatusCode == http.StatusOK {
-		var response *GetZonesByNameResponse
+	if len(zones) == 0 {
+		return nil, fmt.Errorf("Error getting zone '%s': %w", name, ErrNotFound)
+	}
+
+	if len(zones) != 1 {
+		return nil, fmt.Errorf("Error getting zone '%s'. Multiple matching zones found: %w", name, ErrMultipleMatches)
+	}
+
+	return &zones[0], nil
+}
+
+// ListZonesOpts covers all parameters used to list DNS zones
+type ListZonesOpts struct {
+	// Name only returns the zone with exactly this name
+	Name string
+	// SearchName only returns zones whose name contains this string
+	SearchName string
+	// PerPage is the number of zones fetched per request. Defaults to DefaultPerPage.
+	PerPage int
+}
+
+// ListZones reads all DNS zones matching the given options. It follows
+// the pagination of the API until all pages have been read.
+func (c *Client) ListZones(ctx context.Context, opts ListZonesOpts) ([]Zone, error) {
+	query := url.Values{}
+	if opts.Name != "" {
+		query.Set("name", opts.Name)
+	}
+	if opts.SearchName != "" {
+		query.Set("search_name", opts.SearchName)
+	}
+	query.Set("per_page", strconv.Itoa(perPageOrDefault(opts.PerPage)))
+
+	zones := []Zone{}
+	for page := 1; ; page++ {
+		query.Set("page", strconv.Itoa(page))
+		resp, err := c.doGetRequest(ctx, fmt.Sprintf("%s/zones?%s", c.apiEndpoint, query.Encode()))
+		if errors.Is(err, ErrNotFound) && opts.Name != "" && page == 1 {
+			// The API responds with 404 if no zone matches the name filter
+			return zones, nil
+		} else if err != nil {
+			return nil, fmt.Errorf("Error listing zones: %w", err)
+		}
+
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("Error listing zones. HTTP status %d unhandled", resp.StatusCode)
+		}
+
+		var response ZonesResponse
 		err = readAndParseJSONBody(resp, &response)
 		if err != nil {
 			return nil, err
 		}
 
-		if len(response.Zones) != 1 {
-			return nil, fmt.Errorf("Error getting zone '%s'. No matching zone or multiple matching zones found", name)
+		zones = append(zones, response.Zones...)
+		if len(response.Zones) == 0 || !response.Meta.hasNextPage() {
+			return zones, nil
 		}
-
-		return &response.Zones[0], nil
-	} else if resp.StatusCode == http.StatusNotFound {
-		return nil, nil
 	}
-
-	return nil, fmt.Errorf("Error getting Zone. HTTP status %d unhandled", resp.StatusCode)
 }
 
 // CreateZoneOpts covers all parameters used to create a new DNS zone
@@ -260,17 +462,32 @@ type CreateZoneOpts struct {
 	TTL  int    `json:"ttl"`
 }
 
-// CreateZone creates a new DNS zone
-func (c *Client) CreateZone(opts CreateZoneOpts) (*Zone, error) {
+// CreateZone creates a new DNS zone. If the request is retried after an
+// earlier attempt created the zone already, that zone is returned.
+func (c *Client) CreateZone(ctx context.Context, opts CreateZoneOpts) (*Zone, error) {
 
 	if !strings.Contains(opts.Name, ".") {
 		return nil, fmt.Errorf("Error creating zone. The name '%s' is not a valid domain. It must correspond to the schema <domain>.<tld>", opts.Name)
 	}
 
+	var existing *Zone
+	ctx, guard := withRetryGuard(ctx, func(ctx context.Context) (bool, error) {
+		zone, err := c.GetZoneByName(ctx, opts.Name)
+		if errors.Is(err, ErrNotFound) {
+			return false, nil
+		} else if err != nil {
+			return false, err
+		}
+		existing = zone
+		return true, nil
+	})
+
 	reqBody := CreateZoneRequest{Name: opts.Name, TTL: opts.TTL}
-	resp, err := c.doPostRequest("https://dns.hetzner.com/api/v1/zones", reqBody)
-	if err != nil {
-		return nil, fmt.Errorf("Error creating zone. %s", err)
+	resp, err := c.doPostRequest(ctx, fmt.Sprintf("%s/zones", c.apiEndpoint), reqBody)
+	if err != nil && guard.existsAfter(ctx, err) {
+		return existing, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("Error creating zone. %w", err)
 	}
 
 	if resp.StatusCode == http.StatusOK {
@@ -286,53 +503,207 @@ func (c *Client) CreateZone(opts CreateZoneOpts) (*Zone, error) {
 	return nil, fmt.Errorf("Error creating Zone. HTTP status %d unhandled", resp.StatusCode)
 }
 
-// GetRecordByName reads the current state of a DNS Record with a given name and zone id
-func (c *Client) GetRecordByName(zoneID string, name string) (*Record, error) {
-	resp, err := c.doGetRequest(fmt.Sprintf("https://dns.hetzner.com/api/v1/records?zone_id=%s", zoneID))
+// ImportZoneFile replaces the records of the DNS zone with the given id by
+// the records of a zone file in BIND format
+func (c *Client) ImportZoneFile(ctx context.Context, zoneID string, zoneFile string) (*Zone, error) {
+	req, err := c.newRequest(ctx, http.MethodPost, fmt.Sprintf("%s/zones/%s/import", c.apiEndpoint, zoneID), strings.NewReader(zoneFile))
 	if err != nil {
-		return nil, fmt.Errorf("Error getting record %s: %s", name, err)
+		return nil, fmt.Errorf("Error importing zone file into zone %s: %w", zoneID, err)
+	}
+	req.Header.Set("Content-Type", textContentType)
+
+	resp, err := c.doRequest(req)
+	if err != nil {
+		return nil, fmt.Errorf("Error importing zone file into zone %s: %w", zoneID, err)
 	}
 
 	if resp.StatusCode == http.StatusOK {
-		var response *RecordsResponse
+		var response ZoneResponse
 		err = readAndParseJSONBody(resp, &response)
 		if err != nil {
 			return nil, err
 		}
+		return &response.Zone, nil
+	}
+
+	return nil, fmt.Errorf("Error importing zone file. HTTP status %d unhandled", resp.StatusCode)
+}
+
+// ExportZoneFile returns all records of the DNS zone with the given id
+// as a zone file in BIND format
+func (c *Client) ExportZoneFile(ctx context.Context, zoneID string) (string, error) {
+	req, err := c.newRequest(ctx, http.MethodGet, fmt.Sprintf("%s/zones/%s/export", c.apiEndpoint, zoneID), nil)
+	if err != nil {
+		return "", fmt.Errorf("Error exporting zone file of zone %s: %w", zoneID, err)
+	}
+	req.Header.Set("Accept", textContentType)
+
+	resp, err := c.doRequest(req)
+	if err != nil {
+		return "", fmt.Errorf("Error exporting zone file of zone %s: %w", zoneID, err)
+	}
 
-		if len(response.Records) == 0 {
-			return nil, fmt.Errorf("Error getting record '%s'. It seems there are no records in zone %s at all", name, zoneID)
+	if resp.StatusCode == http.StatusOK {
+		body, err := ioutil.ReadAll(resp.Body)
+		defer resp.Body.Close()
+		if err != nil {
+			return "", fmt.Errorf("Error reading HTTP response body %w", err)
 		}
+		return string(body), nil
+	}
 
-		for _, record := range response.Records {
-			if record.Name == name {
-				return &record, nil
-			}
+	return "", fmt.Errorf("Error exporting zone file. HTTP status %d unhandled", resp.StatusCode)
+}
+
+// ValidateZoneFile checks a zone file in BIND format without importing it
+func (c *Client) ValidateZoneFile(ctx context.Context, zoneFile string) (*ZoneFileValidation, error) {
+	// An invalid zone file is rejected the same way every time
+	req, err := c.newRequest(withoutConflictRetries(ctx), http.MethodPost, fmt.Sprintf("%s/zones/file/validate", c.apiEndpoint), strings.NewReader(zoneFile))
+	if err != nil {
+		return nil, fmt.Errorf("Error validating zone file: %w", err)
+	}
+	req.Header.Set("Content-Type", textContentType)
+
+	resp, err := c.doRequest(req)
+	if err != nil {
+		return nil, fmt.Errorf("Error validating zone file: %w", err)
+	}
+
+	if resp.StatusCode == http.StatusOK {
+		var response ZoneFileValidation
+		err = readAndParseJSONBody(resp, &response)
+		if err != nil {
+			return nil, err
 		}
+		return &response, nil
+	}
+
+	return nil, fmt.Errorf("Error validating zone file. HTTP status %d unhandled", resp.StatusCode)
+}
 
-		return nil, fmt.Errorf("Error getting record '%s'. There are records in zone %s, but %s isn't included", name, zoneID, name)
+// GetRecordByName reads the current state of the DNS record of a zone with
+// the given name and type. A name may hold records of several types, e.g.
+// A and TXT, so the type is needed to tell them apart.
+func (c *Client) GetRecordByName(ctx context.Context, zoneID string, name string, recordType string) (*Record, error) {
+	return c.LookupRecord(ctx, LookupRecordOpts{ZoneID: zoneID, Name: name, Type: recordType})
+}
+
+// LookupRecordOpts selects a single record of a DNS zone
+type LookupRecordOpts struct {
+	ZoneID string
+	Name   string
+	// Type is matched case-insensitively
+	Type string
+	// Value only has to be set if the zone has several records with the
+	// same name and type
+	Value string
+}
+
+func (o LookupRecordOpts) matches(record Record) bool {
+	return record.Name == o.Name &&
+		strings.EqualFold(record.Type, o.Type) &&
+		(o.Value == "" || record.Value == o.Value)
+}
+
+func (o LookupRecordOpts) String() string {
+	if o.Value == "" {
+		return fmt.Sprintf("%s %s", o.Name, strings.ToUpper(o.Type))
 	}
+	return fmt.Sprintf("%s %s %s", o.Name, strings.ToUpper(o.Type), o.Value)
+}
 
-	return nil, fmt.Errorf("Error getting Record. HTTP status %d unhandled", resp.StatusCode)
+// LookupRecord reads the current state of the DNS record matching the given
+// options. It fails with ErrNotFound if no record matches and with
+// ErrMultipleMatches if several records match.
+func (c *Client) LookupRecord(ctx context.Context, opts LookupRecordOpts) (*Record, error) {
+	records, err := c.findRecords(ctx, opts)
+	if err != nil {
+		return nil, fmt.Errorf("Error getting record '%s': %w", opts, err)
+	}
+
+	switch len(records) {
+	case 0:
+		return nil, fmt.Errorf("Error getting record '%s'. There is no such record in zone %s: %w", opts, opts.ZoneID, ErrNotFound)
+	case 1:
+		return &records[0], nil
+	}
+
+	ids := make([]string, len(records))
+	for i, record := range records {
+		ids[i] = record.ID
+	}
+	return nil, fmt.Errorf("Error getting record '%s'. There are %d such records in zone %s with the IDs %s: %w", opts, len(records), opts.ZoneID, strings.Join(ids, ", "), ErrMultipleMatches)
+}
+
+// findRecords returns the records of a zone matching the given options
+func (c *Client) findRecords(ctx context.Context, opts LookupRecordOpts) ([]Record, error) {
+	records, err := c.ListRecords(ctx, opts.ZoneID, ListRecordsOpts{})
+	if err != nil {
+		return nil, err
+	}
+
+	var matching []Record
+	for _, record := range records {
+		if opts.matches(record) {
+			matching = append(matching, record)
+		}
+	}
+	return matching, nil
+}
+
+// ListRecordsOpts covers all parameters used to list the records of a DNS zone
+type ListRecordsOpts struct {
+	// PerPage is the number of records fetched per request. Defaults to DefaultPerPage.
+	PerPage int
+}
+
+// ListRecords reads all records of the DNS zone with the given id. It follows
+// the pagination of the API until all pages have been read.
+func (c *Client) ListRecords(ctx context.Context, zoneID string, opts ListRecordsOpts) ([]Record, error) {
+	query := url.Values{}
+	query.Set("zone_id", zoneID)
+	query.Set("per_page", strconv.Itoa(perPageOrDefault(opts.PerPage)))
+
+	records := []Record{}
+	for page := 1; ; page++ {
+		query.Set("page", strconv.Itoa(page))
+		resp, err := c.doGetRequest(ctx, fmt.Sprintf("%s/records?%s", c.apiEndpoint, query.Encode()))
+		if err != nil {
+			return nil, fmt.Errorf("Error listing records of zone %s: %w", zoneID, err)
+		}
+
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("Error listing records. HTTP status %d unhandled", resp.StatusCode)
+		}
+
+		var response RecordsResponse
+		err = readAndParseJSONBody(resp, &response)
+		if err != nil {
+			return nil, err
+		}
+
+		records = append(records, response.Records...)
+		if len(response.Records) == 0 || !response.Meta.hasNextPage() {
+			return records, nil
+		}
+	}
 }
 
 // GetRecord reads the current state of a DNS Record
-func (c *Client) GetRecord(recordID string) (*Record, error) {
-	resp, err := c.doGetRequest(fmt.Sprintf("https://dns.hetzner.com/api/v1/records/%s", recordID))
+func (c *Client) GetRecord(ctx context.Context, recordID string) (*Record, error) {
+	resp, err := c.doGetRequest(ctx, fmt.Sprintf("%s/records/%s", c.apiEndpoint, recordID))
 	if err != nil {
-		return nil, fmt.Errorf("Error getting record %s: %s", recordID, err)
+		return nil, fmt.Errorf("Error getting record %s: %w", recordID, err)
 	}
 
 	if resp.StatusCode == http.StatusOK {
 		var response *RecordResponse
 		err = readAndParseJSONBody(resp, &response)
 		if err != nil {
-			return nil, fmt.Errorf("Error Reading json response of get record %s request: %s", recordID, err)
+			return nil, fmt.Errorf("Error Reading json response of get record %s request: %w", recordID, err)
 		}
 
 		return &response.Record, nil
-	} else if resp.StatusCode == http.StatusNotFound {
-		return nil, nil
 	}
 
 	return nil, fmt.Errorf("Error getting Record. HTTP status %d unhandled", resp.StatusCode)
@@ -347,12 +718,26 @@ type CreateRecordOpts struct {
 	TTL    *int   `json:"ttl,omitempty"`
 }
 
-// CreateRecord create a new DNS records
-func (c *Client) CreateRecord(opts CreateRecordOpts) (*Record, error) {
+// CreateRecord create a new DNS records. If the request is retried after an
+// earlier attempt created the record already, that record is returned
+// instead of creating a duplicate.
+func (c *Client) CreateRecord(ctx context.Context, opts CreateRecordOpts) (*Record, error) {
+	var existing *Record
+	ctx, guard := withRetryGuard(ctx, func(ctx context.Context) (bool, error) {
+		records, err := c.findRecords(ctx, LookupRecordOpts{ZoneID: opts.ZoneID, Name: opts.Name, Type: opts.Type, Value: opts.Value})
+		if err != nil || len(records) == 0 {
+			return false, err
+		}
+		existing = &records[0]
+		return true, nil
+	})
+
 	reqBody := CreateRecordRequest{ZoneID: opts.ZoneID, Name: opts.Name, TTL: opts.TTL, Type: opts.Type, Value: opts.Value}
-	resp, err := c.doPostRequest("https://dns.hetzner.com/api/v1/records", reqBody)
-	if err != nil {
-		return nil, fmt.Errorf("Error creating record %s: %s", opts.Name, err)
+	resp, err := c.doPostRequest(ctx, fmt.Sprintf("%s/records", c.apiEndpoint), reqBody)
+	if err != nil && guard.existsAfter(ctx, err) {
+		return existing, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("Error creating record %s: %w", opts.Name, err)
 	}
 
 	if resp.StatusCode == http.StatusOK {
@@ -369,23 +754,19 @@ func (c *Client) CreateRecord(opts CreateRecordOpts) (*Record, error) {
 }
 
 // DeleteRecord deletes a given record
-func (c *Client) DeleteRecord(id string) error {
-	resp, err := c.doDeleteRequest(fmt.Sprintf("https://dns.hetzner.com/api/v1/records/%s", id))
+func (c *Client) DeleteRecord(ctx context.Context, id string) error {
+	err := c.doDeleteRequest(ctx, fmt.Sprintf("%s/records/%s", c.apiEndpoint, id))
 	if err != nil {
-		return fmt.Errorf("Error deleting zone %s: %s", id, err)
-	}
-
-	if resp.StatusCode == http.StatusOK {
-		return nil
+		return fmt.Errorf("Error deleting record %s: %w", id, err)
 	}
-	return fmt.Errorf("Error deleting Record. HTTP status %d unhandled", resp.StatusCode)
+	return nil
 }
 
 // UpdateRecord create a new DNS records
-func (c *Client) UpdateRecord(record Record) (*Record, error) {
-	resp, err := c.doPutRequest(fmt.Sprintf("https://dns.hetzner.com/api/v1/records/%s", record.ID), record)
+func (c *Client) UpdateRecord(ctx context.Context, record Record) (*Record, error) {
+	resp, err := c.doPutRequest(ctx, fmt.Sprintf("%s/records/%s", c.apiEndpoint, record.ID), record)
 	if err != nil {
-		return nil, fmt.Errorf("Error updating record %s: %s", record.ID, err)
+		return nil, fmt.Errorf("Error updating record %s: %w", record.ID, err)
 	}
 
 	if resp.StatusCode == http.StatusOK {
@@ -401,36 +782,197 @@ func (c *Client) UpdateRecord(record Record) (*Record, error) {
 	return nil, fmt.Errorf("Error creating Record. HTTP status %d unhandled", resp.StatusCode)
 }
 
-func (c *Client) GetPrimaryServer(id string) (*PrimaryServer, error) {
-	resp, err := c.doGetRequest(fmt.Sprintf("https://dns.hetzner.com/api/v1/primary_servers/%s", id))
+// BulkRecordsError is returned by BulkCreateRecords and BulkUpdateRecords
+// if the API rejected some of the records. All other records were
+// processed successfully.
+type BulkRecordsError struct {
+	Operation     string
+	FailedRecords []Record
+}
+
+func (e *BulkRecordsError) Error() string {
+	failed := make([]string, len(e.FailedRecords))
+	for i, record := range e.FailedRecords {
+		failed[i] = fmt.Sprintf("%s %s '%s'", record.Name, record.Type, record.Value)
+	}
+	return fmt.Sprintf("Error %s records. The API rejected %d records: %s", e.Operation, len(e.FailedRecords), strings.Join(failed, ", "))
+}
+
+// BulkCreateRecords creates many DNS records with a single request. If the
+// API rejects some of the records, the records which were created are returned
+// together with a *BulkRecordsError listing the invalid ones. If the request
+// is retried after an earlier attempt created some of the records already,
+// only the other records are sent again instead of creating duplicates.
+func (c *Client) BulkCreateRecords(ctx context.Context, opts []CreateRecordOpts) ([]Record, error) {
+	var existing []Record
+	var missing []CreateRecordOpts
+	guardCtx, guard := withRetryGuard(ctx, func(ctx context.Context) (bool, error) {
+		var err error
+		existing, missing, err = c.findCreatedRecords(ctx, opts)
+		return len(existing) > 0, err
+	})
+
+	records, err := c.bulkCreateRecords(guardCtx, opts)
+	if !guard.exists {
+		return records, err
+	}
+	if len(missing) == 0 {
+		return existing, nil
+	}
+	// Every round creates at least one record, so this ends
+	records, err = c.BulkCreateRecords(ctx, missing)
+	return append(existing, records...), err
+}
+
+// findCreatedRecords splits opts into the records which exist in their zones
+// and the ones which don't.
+func (c *Client) findCreatedRecords(ctx context.Context, opts []CreateRecordOpts) ([]Record, []CreateRecordOpts, error) {
+	zoneRecords := map[string][]Record{}
+	var existing []Record
+	var missing []CreateRecordOpts
+	for _, o := range opts {
+		records, ok := zoneRecords[o.ZoneID]
+		if !ok {
+			var err error
+			records, err = c.ListRecords(ctx, o.ZoneID, ListRecordsOpts{})
+			if err != nil {
+				return nil, nil, err
+			}
+		}
+
+		lookup := LookupRecordOpts{ZoneID: o.ZoneID, Name: o.Name, Type: o.Type, Value: o.Value}
+		found := -1
+		for i, record := range records {
+			if lookup.matches(record) {
+				found = i
+				break
+			}
+		}
+		if found < 0 {
+			missing = append(missing, o)
+		} else {
+			existing = append(existing, records[found])
+			// A record only matches one of several identical records in opts
+			records = append(records[:found:found], records[found+1:]...)
+		}
+		zoneRecords[o.ZoneID] = records
+	}
+	return existing, missing, nil
+}
+
+func (c *Client) bulkCreateRecords(ctx context.Context, opts []CreateRecordOpts) ([]Record, error) {
+	reqBody := BulkCreateRecordsRequest{Records: make([]CreateRecordRequest, len(opts))}
+	for i, o := range opts {
+		reqBody.Records[i] = CreateRecordRequest{ZoneID: o.ZoneID, Name: o.Name, TTL: o.TTL, Type: o.Type, Value: o.Value}
+	}
+
+	resp, err := c.doPostRequest(ctx, fmt.Sprintf("%s/records/bulk", c.apiEndpoint), reqBody)
 	if err != nil {
-		return nil, fmt.Errorf("Error getting primary server %s: %s", id, err)
+		return nil, fmt.Errorf("Error creating %d records: %w", len(opts), err)
+	}
+
+	if resp.StatusCode == http.StatusOK {
+		var response BulkCreateRecordsResponse
+		err = readAndParseJSONBody(resp, &response)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(response.InvalidRecords) > 0 {
+			return response.Records, &BulkRecordsError{Operation: "creating", FailedRecords: response.InvalidRecords}
+		}
+		return response.Records, nil
+	}
+
+	return nil, fmt.Errorf("Error creating Records. HTTP status %d unhandled", resp.StatusCode)
+}
+
+// BulkUpdateRecords updates many DNS records with a single request. If the
+// API rejects some of the records, the records which were updated are returned
+// together with a *BulkRecordsError listing the failed ones.
+func (c *Client) BulkUpdateRecords(ctx context.Context, records []Record) ([]Record, error) {
+	reqBody := BulkUpdateRecordsRequest{Records: records}
+	resp, err := c.doPutRequest(ctx, fmt.Sprintf("%s/records/bulk", c.apiEndpoint), reqBody)
+	if err != nil {
+		return nil, fmt.Errorf("Error updating %d records: %w", len(records), err)
+	}
+
+	if resp.StatusCode == http.StatusOK {
+		var response BulkUpdateRecordsResponse
+		err = readAndParseJSONBody(resp, &response)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(response.FailedRecords) > 0 {
+			return response.Records, &BulkRecordsError{Operation: "updating", FailedRecords: response.FailedRecords}
+		}
+		return response.Records, nil
+	}
+
+	return nil, fmt.Errorf("Error updating Records. HTTP status %d unhandled", resp.StatusCode)
+}
+
+func (c *Client) GetPrimaryServer(ctx context.Context, id string) (*PrimaryServer, error) {
+	resp, err := c.doGetRequest(ctx, fmt.Sprintf("%s/primary_servers/%s", c.apiEndpoint, id))
+	if err != nil {
+		return nil, fmt.Errorf("Error getting primary server %s: %w", id, err)
 	}
 
 	if resp.StatusCode == http.StatusOK {
 		var response *PrimaryServerResponse
 		err = readAndParseJSONBody(resp, &response)
 		if err != nil {
-			return nil, fmt.Errorf("Error Reading json response of get primary server %s request: %s", id, err)
+			return nil, fmt.Errorf("Error Reading json response of get primary server %s request: %w", id, err)
 		}
 
 		return &response.PrimaryServer, nil
-	} else if resp.StatusCode == http.StatusNotFound {
-		return nil, nil
 	}
 
 	return nil, fmt.Errorf("Error getting primary server. HTTP status %d unhandled", resp.StatusCode)
 }
 
-func (c *Client) CreatePrimaryServer(server CreatePrimaryServerRequest) (*PrimaryServer, error) {
+// ListPrimaryServers reads all primary servers of the DNS zone with the given
+// id. It fails with ErrNotFound if the zone doesn't exist.
+func (c *Client) ListPrimaryServers(ctx context.Context, zoneID string) ([]PrimaryServer, error) {
+	query := url.Values{}
+	query.Set("zone_id", zoneID)
+	resp, err := c.doGetRequest(ctx, fmt.Sprintf("%s/primary_servers?%s", c.apiEndpoint, query.Encode()))
+	if errors.Is(err, ErrNotFound) {
+		// The API responds with 404 both if a zone has no primary servers
+		// and if the zone doesn't exist
+		if _, zoneErr := c.GetZone(ctx, zoneID); zoneErr != nil {
+			return nil, fmt.Errorf("Error listing primary servers of zone %s: %w", zoneID, zoneErr)
+		}
+		return []PrimaryServer{}, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("Error listing primary servers of zone %s: %w", zoneID, err)
+	}
+
+	if resp.StatusCode == http.StatusOK {
+		var response PrimaryServersResponse
+		err = readAndParseJSONBody(resp, &response)
+		if err != nil {
+			return nil, err
+		}
+		if response.PrimaryServers == nil {
+			return []PrimaryServer{}, nil
+		}
+		return response.PrimaryServers, nil
+	}
+
+	return nil, fmt.Errorf("Error listing primary servers. HTTP status %d unhandled", resp.StatusCode)
+}
+
+func (c *Client) CreatePrimaryServer(ctx context.Context, server CreatePrimaryServerRequest) (*PrimaryServer, error) {
 	reqBody := CreatePrimaryServerRequest{
 		ZoneID:  server.ZoneID,
 		Address: server.Address,
 		Port:    server.Port,
 	}
-	resp, err := c.doPostRequest("https://dns.hetzner.com/api/v1/primary_servers", reqBody)
+	resp, err := c.doPostRequest(ctx, fmt.Sprintf("%s/primary_servers", c.apiEndpoint), reqBody)
 	if err != nil {
-		return nil, fmt.Errorf("Error creating primary server %s: %s", server.Address, err)
+		return nil, fmt.Errorf("Error creating primary server %s: %w", server.Address, err)
 	}
 
 	if resp.StatusCode == http.StatusOK {
@@ -446,10 +988,10 @@ func (c *Client) CreatePrimaryServer(server CreatePrimaryServerRequest) (*Primar
 	return nil, fmt.Errorf("Error creating primary server. HTTP status %d unhandled", resp.StatusCode)
 }
 
-func (c *Client) UpdatePrimaryServer(server PrimaryServer) (*PrimaryServer, error) {
-	resp, err := c.doPutRequest(fmt.Sprintf("https://dns.hetzner.com/api/v1/primary_servers/%s", server.ID), server)
+func (c *Client) UpdatePrimaryServer(ctx context.Context, server PrimaryServer) (*PrimaryServer, error) {
+	resp, err := c.doPutRequest(ctx, fmt.Sprintf("%s/primary_servers/%s", c.apiEndpoint, server.ID), server)
 	if err != nil {
-		return nil, fmt.Errorf("Error updating primary server %s: %s", server.ID, err)
+		return nil, fmt.Errorf("Error updating primary server %s: %w", server.ID, err)
 	}
 
 	if resp.StatusCode == http.StatusOK {
@@ -465,14 +1007,10 @@ func (c *Client) UpdatePrimaryServer(server PrimaryServer) (*PrimaryServer, erro
 	return nil, fmt.Errorf("Error updating primary server. HTTP status %d unhandled", resp.StatusCode)
 }
 
-func (c *Client) DeletePrimaryServer(id string) error {
-	resp, err := c.doDeleteRequest(fmt.Sprintf("https://dns.hetzner.com/api/v1/primary_servers/%s", id))
+func (c *Client) DeletePrimaryServer(ctx context.Context, id string) error {
+	err := c.doDeleteRequest(ctx, fmt.Sprintf("%s/primary_servers/%s", c.apiEndpoint, id))
 	if err != nil {
-		return fmt.Errorf("Error deleting primary server %s: %s", id, err)
-	}
-
-	if resp.StatusCode == http.StatusOK {
-		return nil
+		return fmt.Errorf("Error deleting primary server %s: %w", id, err)
 	}
-	return fmt.Errorf("Error deleting primary server. HTTP status %d unhandled", resp.StatusCode)
+	return nil
 }
@@ -2,11 +2,21 @@ package api
 
 import (
 	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
 	"io"
 	"io/ioutil"
+	"net"
 	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
 	"testing"
+	"time"
 
+	"github.com/hashicorp/go-retryablehttp"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -17,7 +27,7 @@ func TestClientCreateZoneSuccess(t *testing.T) {
 	client := createTestClient(config)
 
 	opts := CreateZoneOpts{Name: "mydomain.com", TTL: 3600}
-	zone, err := client.CreateZone(opts)
+	zone, err := client.CreateZone(context.Background(), opts)
 
 	assert.NoError(t, err)
 	assert.Equal(t, Zone{ID: "12345", Name: "mydomain.com", TTL: 3600}, *zone)
@@ -32,17 +42,20 @@ func TestClientCreateZoneInvalidDomain(t *testing.T) {
 
 	client := createTestClient(config)
 	opts := CreateZoneOpts{Name: "this.is.invalid", TTL: 3600}
-	_, err := client.CreateZone(opts)
+	_, err := client.CreateZone(context.Background(), opts)
 
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "API returned HTTP 422 Unprocessable Entity error with message: '422 : invalid TLD'")
+	var validationError *ValidationError
+	assert.True(t, errors.As(err, &validationError))
+	assert.Equal(t, 422, validationError.Code)
 }
 
 func TestClientCreateZoneInvalidTLD(t *testing.T) {
 	var irrelevantConfig RequestConfig
 	client := createTestClient(irrelevantConfig)
 	opts := CreateZoneOpts{Name: "thisisinvalid", TTL: 3600}
-	_, err := client.CreateZone(opts)
+	_, err := client.CreateZone(context.Background(), opts)
 
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "'thisisinvalid' is not a valid domain")
@@ -56,7 +69,7 @@ func TestClientUpdateZoneSuccess(t *testing.T) {
 	config := RequestConfig{responseHTTPStatus: http.StatusOK, requestBodyReader: &requestBodyReader, responseBodyJSON: responseBody}
 	client := createTestClient(config)
 
-	updatedZone, err := client.UpdateZone(zoneWithUpdates)
+	updatedZone, err := client.UpdateZone(context.Background(), zoneWithUpdates)
 
 	assert.NoError(t, err)
 	assert.Equal(t, zoneWithUpdates, *updatedZone)
@@ -70,20 +83,69 @@ func TestClientGetZone(t *testing.T) {
 	config := RequestConfig{responseHTTPStatus: http.StatusOK, responseBodyJSON: responseBody}
 	client := createTestClient(config)
 
-	zone, err := client.GetZone("12345678")
+	zone, err := client.GetZone(context.Background(), "12345678")
 
 	assert.NoError(t, err)
 	assert.Equal(t, Zone{ID: "12345678", Name: "zone1.online", TTL: 3600}, *zone)
 }
 
-func TestClientGetZoneReturnNilIfNotFound(t *testing.T) {
-	config := RequestConfig{responseHTTPStatus: http.StatusNotFound}
+func TestClientGetZoneWithAllAttributes(t *testing.T) {
+	responseBody := []byte(`{"zone":{"id":"12345678","name":"zone1.online","ttl":3600,"registrar":"","legacy_dns_host":"","legacy_ns":["ns1.example.com"],"ns":["hydrogen.ns.hetzner.com","oxygen.ns.hetzner.com","helium.ns.hetzner.de"],"created":"2020-08-18 19:11:02.237 +0000 UTC","verified":"2020-08-18 19:20:00 +0000 UTC","modified":"2020-08-28 19:51:41.275 +0000 UTC","project":"project1","owner":"owner1","permission":"","zone_type":{"id":"","name":"","description":"","prices":null},"status":"verified","paused":false,"is_secondary_dns":true,"txt_verification":{"name":"_hetzner","token":"abcdef"},"records_count":4}}`)
+	config := RequestConfig{responseHTTPStatus: http.StatusOK, responseBodyJSON: responseBody}
 	client := createTestClient(config)
 
-	zone, err := client.GetZone("12345678")
+	zone, err := client.GetZone(context.Background(), "12345678")
 
 	assert.NoError(t, err)
+	assert.Equal(t, Zone{
+		ID:              "12345678",
+		Name:            "zone1.online",
+		TTL:             3600,
+		NS:              []string{"hydrogen.ns.hetzner.com", "oxygen.ns.hetzner.com", "helium.ns.hetzner.de"},
+		LegacyNS:        []string{"ns1.example.com"},
+		Project:         "project1",
+		Owner:           "owner1",
+		Status:          "verified",
+		Verified:        "2020-08-18 19:20:00 +0000 UTC",
+		Created:         "2020-08-18 19:11:02.237 +0000 UTC",
+		Modified:        "2020-08-28 19:51:41.275 +0000 UTC",
+		IsSecondaryDNS:  true,
+		RecordsCount:    4,
+		TxtVerification: &TxtVerification{Name: "_hetzner", Token: "abcdef"},
+	}, *zone)
+}
+
+func TestClientUpdateZoneSendsOnlyChangeableAttributes(t *testing.T) {
+	var requestBodyReader io.Reader
+	responseBody := []byte(`{"zone":{"id":"12345678","name":"zone1.online","ttl":60,"status":"verified"}}`)
+	config := RequestConfig{responseHTTPStatus: http.StatusOK, requestBodyReader: &requestBodyReader, responseBodyJSON: responseBody}
+	client := createTestClient(config)
+
+	zone := Zone{ID: "12345678", Name: "zone1.online", TTL: 60, Status: "verified", NS: []string{"hydrogen.ns.hetzner.com"}}
+	_, err := client.UpdateZone(context.Background(), zone)
+
+	assert.NoError(t, err)
+	jsonRequestBody, _ := ioutil.ReadAll(requestBodyReader)
+	assert.Equal(t, `{"id":"12345678","name":"zone1.online","ttl":60}`, string(jsonRequestBody))
+}
+
+func TestClientGetZoneReturnNotFoundError(t *testing.T) {
+	responseBody := []byte(`{"zone":{},"error":{"message":"zone not found","code":404}}`)
+	config := RequestConfig{responseHTTPStatus: http.StatusNotFound, responseBodyJSON: responseBody}
+	client := createTestClient(config)
+
+	zone, err := client.GetZone(context.Background(), "12345678")
+
 	assert.Nil(t, zone)
+	assert.True(t, errors.Is(err, ErrNotFound))
+	var notFoundError *NotFoundError
+	assert.True(t, errors.As(err, &notFoundError))
+	assert.Equal(t, http.StatusNotFound, notFoundError.StatusCode)
+	assert.Equal(t, http.MethodGet, notFoundError.Method)
+	assert.Equal(t, "/api/v1/zones/12345678", notFoundError.Path)
+	assert.Equal(t, "zone not found", notFoundError.Message)
+	assert.Equal(t, 404, notFoundError.Code)
+	assert.Equal(t, string(responseBody), notFoundError.Body)
 }
 
 func TestClientGetZoneByName(t *testing.T) {
@@ -91,27 +153,27 @@ func TestClientGetZoneByName(t *testing.T) {
 	config := RequestConfig{responseHTTPStatus: http.StatusOK, responseBodyJSON: responseBody}
 	client := createTestClient(config)
 
-	zone, err := client.GetZoneByName("zone1.online")
+	zone, err := client.GetZoneByName(context.Background(), "zone1.online")
 
 	assert.NoError(t, err)
 	assert.Equal(t, Zone{ID: "12345678", Name: "zone1.online", TTL: 3600}, *zone)
 }
 
-func TestClientGetZoneByNameReturnNilIfnotFound(t *testing.T) {
+func TestClientGetZoneByNameReturnNotFoundError(t *testing.T) {
 	config := RequestConfig{responseHTTPStatus: http.StatusNotFound}
 	client := createTestClient(config)
 
-	zone, err := client.GetZoneByName("zone1.online")
+	zone, err := client.GetZoneByName(context.Background(), "zone1.online")
 
-	assert.NoError(t, err)
 	assert.Nil(t, zone)
+	assert.True(t, errors.Is(err, ErrNotFound))
 }
 
 func TestClientDeleteZone(t *testing.T) {
 	config := RequestConfig{responseHTTPStatus: http.StatusOK}
 	client := createTestClient(config)
 
-	err := client.DeleteZone("irrelevant")
+	err := client.DeleteZone(context.Background(), "irrelevant")
 
 	assert.NoError(t, err)
 }
@@ -122,7 +184,7 @@ func TestClientGetRecord(t *testing.T) {
 	config := RequestConfig{responseHTTPStatus: http.StatusOK, responseBodyJSON: responseBody}
 	client := createTestClient(config)
 
-	record, err := client.GetRecord("12345678")
+	record, err := client.GetRecord(context.Background(), "12345678")
 
 	assert.NoError(t, err)
 	assert.Equal(t, Record{ZoneID: "wwwlsksjjenm", ID: "12345678", Name: "zone1.online", TTL: &aTTL, Type: "A", Value: "192.168.1.1"}, *record)
@@ -133,20 +195,21 @@ func TestClientGetRecordWithUndefinedTTL(t *testing.T) {
 	config := RequestConfig{responseHTTPStatus: http.StatusOK, responseBodyJSON: responseBody}
 	client := createTestClient(config)
 
-	record, err := client.GetRecord("12345678")
+	record, err := client.GetRecord(context.Background(), "12345678")
 
 	assert.NoError(t, err)
 	assert.Equal(t, Record{ZoneID: "wwwlsksjjenm", ID: "12345678", Name: "zone1.online", TTL: nil, Type: "A", Value: "192.168.1.1"}, *record)
 }
 
-func TestClientGetRecordReturnNilIfNotFound(t *testing.T) {
+func TestClientGetRecordReturnNotFoundError(t *testing.T) {
 	config := RequestConfig{responseHTTPStatus: http.StatusNotFound}
 	client := createTestClient(config)
 
-	record, err := client.GetRecord("irrelevant")
+	record, err := client.GetRecord(context.Background(), "irrelevant")
 
-	assert.NoError(t, err)
 	assert.Nil(t, record)
+	var notFoundError *NotFoundError
+	assert.True(t, errors.As(err, &notFoundError))
 }
 
 func TestClientCreateRecordSuccess(t *testing.T) {
@@ -157,7 +220,7 @@ func TestClientCreateRecordSuccess(t *testing.T) {
 
 	aTTL := 3600
 	opts := CreateRecordOpts{ZoneID: "wwwlsksjjenm", Name: "zone1.online", TTL: &aTTL, Type: "A", Value: "192.168.1.1"}
-	record, err := client.CreateRecord(opts)
+	record, err := client.CreateRecord(context.Background(), opts)
 
 	assert.NoError(t, err)
 	assert.Equal(t, Record{ZoneID: "wwwlsksjjenm", ID: "12345678", Name: "zone1.online", TTL: &aTTL, Type: "A", Value: "192.168.1.1"}, *record)
@@ -170,7 +233,7 @@ func TestClientRecordZone(t *testing.T) {
 	config := RequestConfig{responseHTTPStatus: http.StatusOK}
 	client := createTestClient(config)
 
-	err := client.DeleteRecord("irrelevant")
+	err := client.DeleteRecord(context.Background(), "irrelevant")
 
 	assert.NoError(t, err)
 }
@@ -184,7 +247,7 @@ func TestClientUpdateRecordSuccess(t *testing.T) {
 	config := RequestConfig{responseHTTPStatus: http.StatusOK, requestBodyReader: &requestBodyReader, responseBodyJSON: responseBody}
 	client := createTestClient(config)
 
-	updatedRecord, err := client.UpdateRecord(recordWithUpdates)
+	updatedRecord, err := client.UpdateRecord(context.Background(), recordWithUpdates)
 
 	assert.NoError(t, err)
 	assert.Equal(t, recordWithUpdates, *updatedRecord)
@@ -193,30 +256,586 @@ func TestClientUpdateRecordSuccess(t *testing.T) {
 	assert.Equal(t, recordWithUpdatesJSON, string(jsonRequestBody))
 }
 
+func TestClientBulkCreateRecordsSuccess(t *testing.T) {
+	var requestBodyReader io.Reader
+	responseBody := []byte(`{"records":[{"zone_id":"zone1","id":"1","name":"www","type":"A","value":"192.168.1.1"},{"zone_id":"zone1","id":"2","name":"mail","type":"A","value":"192.168.1.2","ttl":60}],"valid_records":[],"invalid_records":[]}`)
+	config := RequestConfig{responseHTTPStatus: http.StatusOK, requestBodyReader: &requestBodyReader, responseBodyJSON: responseBody}
+	client := createTestClient(config)
+
+	aTTL := 60
+	opts := []CreateRecordOpts{
+		{ZoneID: "zone1", Name: "www", Type: "A", Value: "192.168.1.1"},
+		{ZoneID: "zone1", Name: "mail", Type: "A", Value: "192.168.1.2", TTL: &aTTL},
+	}
+	records, err := client.BulkCreateRecords(context.Background(), opts)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []Record{
+		{ZoneID: "zone1", ID: "1", Name: "www", Type: "A", Value: "192.168.1.1"},
+		{ZoneID: "zone1", ID: "2", Name: "mail", Type: "A", Value: "192.168.1.2", TTL: &aTTL},
+	}, records)
+	jsonRequestBody, _ := ioutil.ReadAll(requestBodyReader)
+	assert.Equal(t, `{"records":[{"zone_id":"zone1","type":"A","name":"www","value":"192.168.1.1"},{"zone_id":"zone1","type":"A","name":"mail","value":"192.168.1.2","ttl":60}]}`, string(jsonRequestBody))
+}
+
+func TestClientBulkCreateRecordsReportsInvalidRecords(t *testing.T) {
+	responseBody := []byte(`{"records":[{"zone_id":"zone1","id":"1","name":"www","type":"A","value":"192.168.1.1"}],"valid_records":[],"invalid_records":[{"zone_id":"zone1","name":"mail","type":"AAAA","value":"not-an-ip"}]}`)
+	config := RequestConfig{responseHTTPStatus: http.StatusOK, responseBodyJSON: responseBody}
+	client := createTestClient(config)
+
+	opts := []CreateRecordOpts{
+		{ZoneID: "zone1", Name: "www", Type: "A", Value: "192.168.1.1"},
+		{ZoneID: "zone1", Name: "mail", Type: "AAAA", Value: "not-an-ip"},
+	}
+	records, err := client.BulkCreateRecords(context.Background(), opts)
+
+	assert.Len(t, records, 1)
+	var bulkErr *BulkRecordsError
+	assert.True(t, errors.As(err, &bulkErr))
+	assert.Equal(t, []Record{{ZoneID: "zone1", Name: "mail", Type: "AAAA", Value: "not-an-ip"}}, bulkErr.FailedRecords)
+	assert.Contains(t, err.Error(), "mail AAAA 'not-an-ip'")
+}
+
+func TestClientBulkUpdateRecordsSuccess(t *testing.T) {
+	var requestBodyReader io.Reader
+	responseBody := []byte(`{"records":[{"zone_id":"zone1","id":"1","name":"www","type":"A","value":"192.168.1.3"}],"failed_records":[]}`)
+	config := RequestConfig{responseHTTPStatus: http.StatusOK, requestBodyReader: &requestBodyReader, responseBodyJSON: responseBody}
+	client := createTestClient(config)
+
+	recordWithUpdates := Record{ZoneID: "zone1", ID: "1", Name: "www", Type: "A", Value: "192.168.1.3"}
+	records, err := client.BulkUpdateRecords(context.Background(), []Record{recordWithUpdates})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []Record{recordWithUpdates}, records)
+	jsonRequestBody, _ := ioutil.ReadAll(requestBodyReader)
+	assert.Equal(t, `{"records":[{"zone_id":"zone1","id":"1","type":"A","name":"www","value":"192.168.1.3"}]}`, string(jsonRequestBody))
+}
+
+func TestClientBulkUpdateRecordsReportsFailedRecords(t *testing.T) {
+	responseBody := []byte(`{"records":[],"failed_records":[{"zone_id":"zone1","id":"1","name":"www","type":"A","value":"invalid"}]}`)
+	config := RequestConfig{responseHTTPStatus: http.StatusOK, responseBodyJSON: responseBody}
+	client := createTestClient(config)
+
+	_, err := client.BulkUpdateRecords(context.Background(), []Record{{ZoneID: "zone1", ID: "1", Name: "www", Type: "A", Value: "invalid"}})
+
+	var bulkErr *BulkRecordsError
+	assert.True(t, errors.As(err, &bulkErr))
+	assert.Equal(t, "1", bulkErr.FailedRecords[0].ID)
+}
+
+func TestClientImportZoneFile(t *testing.T) {
+	zoneFile := "$ORIGIN zone1.online.\n$TTL 3600\nwww IN A 192.168.1.1\n"
+	var requestBodyReader io.Reader
+	var requestHeader http.Header
+	var requestURL string
+	responseBody := []byte(`{"zone":{"id":"12345678","name":"zone1.online","ttl":3600}}`)
+	config := RequestConfig{responseHTTPStatus: http.StatusOK, requestBodyReader: &requestBodyReader, requestHeader: &requestHeader, requestURL: &requestURL, responseBodyJSON: responseBody}
+	client := createTestClient(config)
+
+	zone, err := client.ImportZoneFile(context.Background(), "12345678", zoneFile)
+
+	assert.NoError(t, err)
+	assert.Equal(t, Zone{ID: "12345678", Name: "zone1.online", TTL: 3600}, *zone)
+	assert.Equal(t, "https://dns.hetzner.com/api/v1/zones/12345678/import", requestURL)
+	assert.Equal(t, "text/plain; charset=utf-8", requestHeader.Get("Content-Type"))
+	requestBody, _ := ioutil.ReadAll(requestBodyReader)
+	assert.Equal(t, zoneFile, string(requestBody))
+}
+
+func TestClientExportZoneFile(t *testing.T) {
+	zoneFile := "$ORIGIN zone1.online.\n$TTL 3600\nwww IN A 192.168.1.1\n"
+	var requestHeader http.Header
+	var requestURL string
+	config := RequestConfig{responseHTTPStatus: http.StatusOK, requestHeader: &requestHeader, requestURL: &requestURL, responseBodyJSON: []byte(zoneFile)}
+	client := createTestClient(config)
+
+	exported, err := client.ExportZoneFile(context.Background(), "12345678")
+
+	assert.NoError(t, err)
+	assert.Equal(t, zoneFile, exported)
+	assert.Equal(t, "https://dns.hetzner.com/api/v1/zones/12345678/export", requestURL)
+	assert.Equal(t, "text/plain; charset=utf-8", requestHeader.Get("Accept"))
+}
+
+func TestClientValidateZoneFile(t *testing.T) {
+	responseBody := []byte(`{"parsed_records":2,"valid_records":[{"name":"www","type":"A","value":"192.168.1.1"}],"invalid_records":[{"name":"mail","type":"AAAA","value":"invalid"}]}`)
+	var requestURL string
+	config := RequestConfig{responseHTTPStatus: http.StatusOK, requestURL: &requestURL, responseBodyJSON: responseBody}
+	client := createTestClient(config)
+
+	validation, err := client.ValidateZoneFile(context.Background(), "irrelevant")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "https://dns.hetzner.com/api/v1/zones/file/validate", requestURL)
+	assert.Equal(t, 2, validation.ParsedRecords)
+	assert.Len(t, validation.ValidRecords, 1)
+	assert.Equal(t, []Record{{Name: "mail", Type: "AAAA", Value: "invalid"}}, validation.InvalidRecords)
+	assert.False(t, validation.IsValid())
+}
+
+func TestClientListPrimaryServers(t *testing.T) {
+	responseBody := []byte(`{"primary_servers":[{"id":"1","port":53,"zone_id":"zone1","address":"1.1.1.1"},{"id":"2","port":5353,"zone_id":"zone1","address":"2.2.2.2"}]}`)
+	var requestURL string
+	config := RequestConfig{responseHTTPStatus: http.StatusOK, requestURL: &requestURL, responseBodyJSON: responseBody}
+	client := createTestClient(config)
+
+	primaryServers, err := client.ListPrimaryServers(context.Background(), "zone1")
+
+	port53 := 53
+	port5353 := 5353
+	assert.NoError(t, err)
+	assert.Equal(t, "https://dns.hetzner.com/api/v1/primary_servers?zone_id=zone1", requestURL)
+	assert.Equal(t, []PrimaryServer{
+		{ID: "1", Port: &port53, ZoneID: "zone1", Address: "1.1.1.1"},
+		{ID: "2", Port: &port5353, ZoneID: "zone1", Address: "2.2.2.2"},
+	}, primaryServers)
+}
+
+func TestClientListPrimaryServersReturnsEmptyListIfNotFound(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/zones/zone1" {
+			w.Write([]byte(`{"zone":{"id":"zone1","name":"zone1.online","ttl":3600}}`))
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"message":"primary servers not found"}`))
+	}))
+	defer server.Close()
+	client, _ := NewClient("irrelevant", ClientOpts{Endpoint: server.URL})
+
+	primaryServers, err := client.ListPrimaryServers(context.Background(), "zone1")
+
+	assert.NoError(t, err)
+	assert.Empty(t, primaryServers)
+}
+
+func TestClientListPrimaryServersFailsIfZoneNotFound(t *testing.T) {
+	config := RequestConfig{responseHTTPStatus: http.StatusNotFound}
+	client := createTestClient(config)
+
+	primaryServers, err := client.ListPrimaryServers(context.Background(), "zone1")
+
+	assert.Nil(t, primaryServers)
+	assert.True(t, errors.Is(err, ErrNotFound), "unexpected error %v", err)
+}
+
 func TestClientHandleUnauthorizedRequest(t *testing.T) {
 	responseBody := []byte(`{"message":"Invalid API key"}`)
 	config := RequestConfig{responseHTTPStatus: http.StatusUnauthorized, responseBodyJSON: responseBody}
 	client := createTestClient(config)
 
 	opts := CreateZoneOpts{Name: "mydomain.com", TTL: 3600}
-	_, err := client.CreateZone(opts)
+	_, err := client.CreateZone(context.Background(), opts)
 
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "'Invalid API key'", "Error message didn't contain error message from API.")
+	assert.True(t, errors.Is(err, ErrUnauthorized))
+}
+
+func TestClientHandleRateLimitedRequest(t *testing.T) {
+	responseBody := []byte(`{"message":"API rate limit exceeded"}`)
+	config := RequestConfig{responseHTTPStatus: http.StatusTooManyRequests, responseBodyJSON: responseBody, responseHeader: http.Header{"Retry-After": []string{"7"}}}
+	client := createTestClient(config)
+
+	_, err := client.GetZone(context.Background(), "12345678")
+
+	var rateLimitedError *RateLimitedError
+	assert.True(t, errors.As(err, &rateLimitedError))
+	assert.Equal(t, 7*time.Second, rateLimitedError.RetryAfter)
+	assert.Equal(t, "API rate limit exceeded", rateLimitedError.Message)
+}
+
+func TestClientHandleServerError(t *testing.T) {
+	config := RequestConfig{responseHTTPStatus: http.StatusBadGateway, responseBodyJSON: []byte(`Bad Gateway`)}
+	client := createTestClient(config)
+
+	err := client.DeleteRecord(context.Background(), "12345678")
+
+	assert.True(t, errors.Is(err, ErrServer))
+	var serverError *ServerError
+	assert.True(t, errors.As(err, &serverError))
+	assert.Equal(t, http.MethodDelete, serverError.Method)
+	assert.Equal(t, "Bad Gateway", serverError.Body)
+}
+
+func TestClientHandleOtherClientError(t *testing.T) {
+	config := RequestConfig{responseHTTPStatus: http.StatusForbidden, responseBodyJSON: []byte(`{"message":"forbidden"}`)}
+	client := createTestClient(config)
+
+	_, err := client.GetRecord(context.Background(), "12345678")
+
+	var apiError *APIError
+	assert.True(t, errors.As(err, &apiError))
+	assert.Equal(t, http.StatusForbidden, apiError.StatusCode)
+	assert.False(t, errors.Is(err, ErrNotFound))
+}
+
+func TestClientUsesConfiguredEndpoint(t *testing.T) {
+	var requestURL string
+	responseBody := []byte(`{"zone":{"id":"12345678","name":"zone1.online","ttl":3600}}`)
+	config := RequestConfig{responseHTTPStatus: http.StatusOK, responseBodyJSON: responseBody, requestURL: &requestURL}
+	client := createTestClient(config)
+	client.apiEndpoint = "http://localhost:8080/api/v1"
+
+	_, err := client.GetZone(context.Background(), "12345678")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "http://localhost:8080/api/v1/zones/12345678", requestURL)
+}
+
+func TestNewClientTrimsTrailingSlashOfEndpoint(t *testing.T) {
+	client, diags := NewClient("irrelevant", ClientOpts{Endpoint: "http://localhost:8080/api/v1/"})
+
+	assert.False(t, diags.HasError())
+	assert.Equal(t, "http://localhost:8080/api/v1", client.apiEndpoint)
+}
+
+func TestNewClientUsesDefaultEndpointIfEmpty(t *testing.T) {
+	client, diags := NewClient("irrelevant", ClientOpts{Endpoint: ""})
+
+	assert.False(t, diags.HasError())
+	assert.Equal(t, DefaultAPIEndpoint, client.apiEndpoint)
+}
+
+func TestNewClientRejectsRelativeEndpoint(t *testing.T) {
+	_, diags := NewClient("irrelevant", ClientOpts{Endpoint: "dns.hetzner.com/api/v1"})
+
+	assert.True(t, diags.HasError())
+}
+
+func TestNewClientUsesDefaultRetryPolicy(t *testing.T) {
+	client, diags := NewClient("irrelevant", ClientOpts{})
+
+	assert.False(t, diags.HasError())
+	retryableClient := client.httpClient.Transport.(*retryablehttp.RoundTripper).Client
+	assert.Equal(t, DefaultMaxRetries, retryableClient.RetryMax)
+	assert.Equal(t, DefaultRetryWaitMin, retryableClient.RetryWaitMin)
+	assert.Equal(t, DefaultRetryWaitMax, retryableClient.RetryWaitMax)
+}
+
+func TestNewClientAppliesRetryPolicy(t *testing.T) {
+	maxRetries := 0
+	waitMin := 100 * time.Millisecond
+	waitMax := time.Second
+	client, diags := NewClient("irrelevant", ClientOpts{MaxRetries: &maxRetries, RetryWaitMin: &waitMin, RetryWaitMax: &waitMax})
+
+	assert.False(t, diags.HasError())
+	retryableClient := client.httpClient.Transport.(*retryablehttp.RoundTripper).Client
+	assert.Equal(t, 0, retryableClient.RetryMax)
+	assert.Equal(t, 100*time.Millisecond, retryableClient.RetryWaitMin)
+	assert.Equal(t, time.Second, retryableClient.RetryWaitMax)
+}
+
+func TestNewClientKeepsZeroRetryWait(t *testing.T) {
+	var noWait time.Duration
+	client, diags := NewClient("irrelevant", ClientOpts{RetryWaitMin: &noWait, RetryWaitMax: &noWait})
+
+	assert.False(t, diags.HasError())
+	retryableClient := client.httpClient.Transport.(*retryablehttp.RoundTripper).Client
+	assert.Equal(t, time.Duration(0), retryableClient.RetryWaitMin)
+	assert.Equal(t, time.Duration(0), retryableClient.RetryWaitMax)
+}
+
+func TestNewClientAdjustsDefaultRetryWaitToExplicitRetryWait(t *testing.T) {
+	shortWait := 500 * time.Millisecond
+	client, diags := NewClient("irrelevant", ClientOpts{RetryWaitMax: &shortWait})
+
+	assert.False(t, diags.HasError())
+	retryableClient := client.httpClient.Transport.(*retryablehttp.RoundTripper).Client
+	assert.Equal(t, shortWait, retryableClient.RetryWaitMin)
+	assert.Equal(t, shortWait, retryableClient.RetryWaitMax)
+
+	longWait := time.Minute
+	client, diags = NewClient("irrelevant", ClientOpts{RetryWaitMin: &longWait})
+
+	assert.False(t, diags.HasError())
+	retryableClient = client.httpClient.Transport.(*retryablehttp.RoundTripper).Client
+	assert.Equal(t, longWait, retryableClient.RetryWaitMin)
+	assert.Equal(t, longWait, retryableClient.RetryWaitMax)
+}
+
+func TestNewClientRejectsInvalidRetryPolicy(t *testing.T) {
+	negative := -1
+	negativeWait := -time.Second
+	minute := time.Minute
+	second := time.Second
+	tests := map[string]ClientOpts{
+		"negative max retries":     {MaxRetries: &negative},
+		"negative min wait":        {RetryWaitMin: &negativeWait},
+		"min wait above max wait":  {RetryWaitMin: &minute, RetryWaitMax: &second},
+		"negative request timeout": {RequestTimeout: -time.Second},
+	}
+
+	for name, opts := range tests {
+		_, diags := NewClient("irrelevant", opts)
+		assert.True(t, diags.HasError(), name)
+	}
+}
+
+func TestNewClientSendsUserAgent(t *testing.T) {
+	var userAgents []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userAgents = append(userAgents, r.Header.Get("User-Agent"))
+		w.Write([]byte(`{"zone":{"id":"12345678","name":"zone1.online","ttl":3600}}`))
+	}))
+	defer server.Close()
+
+	defaultClient, _ := NewClient("irrelevant", ClientOpts{Endpoint: server.URL})
+	_, err := defaultClient.GetZone(context.Background(), "12345678")
+	assert.NoError(t, err)
+	client, _ := NewClient("irrelevant", ClientOpts{Endpoint: server.URL, UserAgent: "terraform-provider-hetznerdns/1.2.3 (+terraform 1.0.0)"})
+	_, err = client.GetZone(context.Background(), "12345678")
+	assert.NoError(t, err)
+
+	assert.Equal(t, []string{DefaultUserAgent, "terraform-provider-hetznerdns/1.2.3 (+terraform 1.0.0)"}, userAgents)
+}
+
+func TestClientListZonesFollowsPagination(t *testing.T) {
+	pages := map[string][]byte{
+		"1": []byte(`{"zones":[{"id":"1","name":"zone1.online","ttl":3600}],"meta":{"pagination":{"page":1,"per_page":1,"last_page":2,"total_entries":2}}}`),
+		"2": []byte(`{"zones":[{"id":"2","name":"zone2.online","ttl":60}],"meta":{"pagination":{"page":2,"per_page":1,"last_page":2,"total_entries":2}}}`),
+	}
+	var requestURLs []string
+	client := createPagedTestClient(pages, &requestURLs)
+
+	zones, err := client.ListZones(context.Background(), ListZonesOpts{SearchName: "zone", PerPage: 1})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []Zone{{ID: "1", Name: "zone1.online", TTL: 3600}, {ID: "2", Name: "zone2.online", TTL: 60}}, zones)
+	assert.Equal(t, []string{
+		"https://dns.hetzner.com/api/v1/zones?page=1&per_page=1&search_name=zone",
+		"https://dns.hetzner.com/api/v1/zones?page=2&per_page=1&search_name=zone",
+	}, requestURLs)
+}
+
+func TestClientListZonesWithoutPaginationMeta(t *testing.T) {
+	pages := map[string][]byte{
+		"1": []byte(`{"zones":[{"id":"1","name":"zone1.online","ttl":3600}]}`),
+	}
+	var requestURLs []string
+	client := createPagedTestClient(pages, &requestURLs)
+
+	zones, err := client.ListZones(context.Background(), ListZonesOpts{})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []Zone{{ID: "1", Name: "zone1.online", TTL: 3600}}, zones)
+	assert.Len(t, requestURLs, 1)
+}
+
+func TestClientListZonesWithoutMatchingName(t *testing.T) {
+	var requestURLs []string
+	client := createPagedTestClient(map[string][]byte{}, &requestURLs)
+
+	zones, err := client.ListZones(context.Background(), ListZonesOpts{Name: "zone1.online"})
+
+	assert.NoError(t, err)
+	assert.Empty(t, zones)
+}
+
+func TestClientListZonesFailsOnNotFound(t *testing.T) {
+	var requestURLs []string
+	client := createPagedTestClient(map[string][]byte{}, &requestURLs)
+
+	_, err := client.ListZones(context.Background(), ListZonesOpts{})
+
+	assert.True(t, errors.Is(err, ErrNotFound), "unexpected error %v", err)
+}
+
+func TestClientListZonesFailsOnMissingPage(t *testing.T) {
+	pages := map[string][]byte{
+		"1": []byte(`{"zones":[{"id":"1","name":"zone1.online","ttl":3600}],"meta":{"pagination":{"page":1,"per_page":1,"last_page":3,"total_entries":3}}}`),
+		"3": []byte(`{"zones":[{"id":"3","name":"zone3.online","ttl":3600}],"meta":{"pagination":{"page":3,"per_page":1,"last_page":3,"total_entries":3}}}`),
+	}
+	var requestURLs []string
+	client := createPagedTestClient(pages, &requestURLs)
+
+	for _, opts := range []ListZonesOpts{{PerPage: 1}, {Name: "zone1.online", PerPage: 1}} {
+		zones, err := client.ListZones(context.Background(), opts)
+
+		assert.Nil(t, zones)
+		assert.True(t, errors.Is(err, ErrNotFound), "unexpected error %v", err)
+	}
+}
+
+func TestClientListRecordsFollowsPagination(t *testing.T) {
+	pages := map[string][]byte{
+		"1": []byte(`{"records":[{"zone_id":"zone1","id":"1","name":"www","type":"A","value":"192.168.1.1"}],"meta":{"pagination":{"page":1,"per_page":1,"last_page":2,"total_entries":2}}}`),
+		"2": []byte(`{"records":[{"zone_id":"zone1","id":"2","name":"mail","type":"A","value":"192.168.1.2"}],"meta":{"pagination":{"page":2,"per_page":1,"last_page":2,"total_entries":2}}}`),
+	}
+	var requestURLs []string
+	client := createPagedTestClient(pages, &requestURLs)
+
+	records, err := client.ListRecords(context.Background(), "zone1", ListRecordsOpts{PerPage: 1})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []Record{
+		{ZoneID: "zone1", ID: "1", Name: "www", Type: "A", Value: "192.168.1.1"},
+		{ZoneID: "zone1", ID: "2", Name: "mail", Type: "A", Value: "192.168.1.2"},
+	}, records)
+	assert.Equal(t, []string{
+		"https://dns.hetzner.com/api/v1/records?page=1&per_page=1&zone_id=zone1",
+		"https://dns.hetzner.com/api/v1/records?page=2&per_page=1&zone_id=zone1",
+	}, requestURLs)
+}
+
+func TestClientGetRecordByNameSearchesAllPages(t *testing.T) {
+	pages := map[string][]byte{
+		"1": []byte(`{"records":[{"zone_id":"zone1","id":"1","name":"www","type":"A","value":"192.168.1.1"}],"meta":{"pagination":{"page":1,"per_page":100,"last_page":2,"total_entries":101}}}`),
+		"2": []byte(`{"records":[{"zone_id":"zone1","id":"2","name":"mail","type":"A","value":"192.168.1.2"}],"meta":{"pagination":{"page":2,"per_page":100,"last_page":2,"total_entries":101}}}`),
+	}
+	var requestURLs []string
+	client := createPagedTestClient(pages, &requestURLs)
+
+	record, err := client.GetRecordByName(context.Background(), "zone1", "mail", "A")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "2", record.ID)
+}
+
+func TestClientGetRecordByNameMatchesType(t *testing.T) {
+	pages := map[string][]byte{
+		"1": []byte(`{"records":[{"zone_id":"zone1","id":"1","name":"www","type":"TXT","value":"hello"},{"zone_id":"zone1","id":"2","name":"www","type":"A","value":"192.168.1.1"}]}`),
+	}
+	var requestURLs []string
+	client := createPagedTestClient(pages, &requestURLs)
+
+	record, err := client.GetRecordByName(context.Background(), "zone1", "www", "a")
+	assert.NoError(t, err)
+	assert.Equal(t, "2", record.ID)
+
+	_, err = client.GetRecordByName(context.Background(), "zone1", "www", "AAAA")
+	assert.True(t, errors.Is(err, ErrNotFound))
+}
+
+func TestClientLookupRecordFailsOnMultipleMatches(t *testing.T) {
+	pages := map[string][]byte{
+		"1": []byte(`{"records":[{"zone_id":"zone1","id":"1","name":"@","type":"MX","value":"10 mx1.example.com."},{"zone_id":"zone1","id":"2","name":"@","type":"MX","value":"20 mx2.example.com."}]}`),
+	}
+	var requestURLs []string
+	client := createPagedTestClient(pages, &requestURLs)
+
+	_, err := client.LookupRecord(context.Background(), LookupRecordOpts{ZoneID: "zone1", Name: "@", Type: "MX"})
+	assert.True(t, errors.Is(err, ErrMultipleMatches))
+	assert.Contains(t, err.Error(), "IDs 1, 2")
+
+	record, err := client.LookupRecord(context.Background(), LookupRecordOpts{ZoneID: "zone1", Name: "@", Type: "MX", Value: "20 mx2.example.com."})
+	assert.NoError(t, err)
+	assert.Equal(t, "2", record.ID)
+}
+
+func TestClientAbortsRetriesWhenContextIsCanceled(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+	client, _ := NewClient("irrelevant", ClientOpts{Endpoint: server.URL})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err := client.GetZone(ctx, "12345678")
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), context.DeadlineExceeded.Error())
+	assert.Less(t, int64(time.Since(start)), int64(2*time.Second), "Retries should stop as soon as the context is done")
+}
+
+func TestClientReusesConnections(t *testing.T) {
+	var newConnections int32
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"zone":{"id":"12345678","name":"zone1.online","ttl":3600}}`))
+	}))
+	server.Config.ConnState = func(conn net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			atomic.AddInt32(&newConnections, 1)
+		}
+	}
+	server.Start()
+	defer server.Close()
+	client, _ := NewClient("irrelevant", ClientOpts{Endpoint: server.URL})
+
+	for i := 0; i < 5; i++ {
+		_, err := client.GetZone(context.Background(), "12345678")
+		assert.NoError(t, err)
+	}
+
+	assert.Equal(t, int32(1), atomic.LoadInt32(&newConnections))
+}
+
+func TestClientSendsRequestsThroughProxy(t *testing.T) {
+	var proxiedURL, proxyAuthorization string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxiedURL = r.URL.String()
+		proxyAuthorization = r.Header.Get("Proxy-Authorization")
+		w.Write([]byte(`{"zone":{"id":"12345678","name":"zone1.online","ttl":3600}}`))
+	}))
+	defer proxy.Close()
+	proxyURL, _ := url.Parse(proxy.URL)
+	proxyURL.User = url.UserPassword("user", "secret")
+
+	client, diags := NewClient("irrelevant", ClientOpts{Endpoint: "http://dns.example.com/api/v1", ProxyURL: proxyURL.String()})
+	assert.False(t, diags.HasError())
+	_, err := client.GetZone(context.Background(), "12345678")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "http://dns.example.com/api/v1/zones/12345678", proxiedURL)
+	assert.Equal(t, "Basic "+base64.StdEncoding.EncodeToString([]byte("user:secret")), proxyAuthorization)
+}
+
+func TestNewClientRejectsInvalidProxyURL(t *testing.T) {
+	_, diags := NewClient("irrelevant", ClientOpts{ProxyURL: "proxy.example.com:3128"})
+
+	assert.True(t, diags.HasError())
+}
+
+func TestClientTrustsConfiguredCACert(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"zone":{"id":"12345678","name":"zone1.online","ttl":3600}}`))
+	}))
+	defer server.Close()
+	caCertPEM := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}))
+
+	tests := map[string]struct {
+		opts  ClientOpts
+		valid bool
+	}{
+		"system CAs only":      {ClientOpts{Endpoint: server.URL}, false},
+		"configured CA":        {ClientOpts{Endpoint: server.URL, CACertPEM: caCertPEM}, true},
+		"insecure skip verify": {ClientOpts{Endpoint: server.URL, InsecureSkipVerify: true}, true},
+	}
+
+	for name, test := range tests {
+		client, diags := NewClient("irrelevant", test.opts)
+		assert.False(t, diags.HasError(), name)
+
+		_, err := client.GetZone(context.Background(), "12345678")
+
+		if test.valid {
+			assert.NoError(t, err, name)
+		} else {
+			assert.Error(t, err, name)
+		}
+	}
+}
+
+func TestNewClientRejectsInvalidCACert(t *testing.T) {
+	_, diags := NewClient("irrelevant", ClientOpts{CACertPEM: "not a certificate"})
+
+	assert.True(t, diags.HasError())
 }
 
 type RequestConfig struct {
 	responseHTTPStatus int
 	responseBodyJSON   []byte
 	requestBodyReader  *io.Reader
+	requestURL         *string
+	requestHeader      *http.Header
+	responseHeader     http.Header
 }
 
 func createTestClient(config RequestConfig) Client {
 	fakeHTTPClient := TestClient{config: config}
-	createFakeHTTPClient := func() *http.Client {
-		return &http.Client{Transport: fakeHTTPClient}
-	}
-	return Client{apiToken: "irrelevant", createHTTPClient: createFakeHTTPClient}
+	return Client{scheduler: newRequestScheduler(), apiToken: "irrelevant", apiEndpoint: DefaultAPIEndpoint, httpClient: &http.Client{Transport: fakeHTTPClient}}
 }
 
 type TestClient struct {
@@ -228,11 +847,109 @@ func (f TestClient) RoundTrip(req *http.Request) (*http.Response, error) {
 	if req.Body != nil && f.config.requestBodyReader != nil {
 		*f.config.requestBodyReader = req.Body
 	}
+	if f.config.requestURL != nil {
+		*f.config.requestURL = req.URL.String()
+	}
+	if f.config.requestHeader != nil {
+		*f.config.requestHeader = req.Header
+	}
 
 	var jsonBody io.ReadCloser = nil
 	if f.config.responseBodyJSON != nil {
 		jsonBody = ioutil.NopCloser(bytes.NewReader(f.config.responseBodyJSON))
 	}
-	resp := http.Response{StatusCode: f.config.responseHTTPStatus, Body: jsonBody}
+	resp := http.Response{StatusCode: f.config.responseHTTPStatus, Header: f.config.responseHeader, Body: jsonBody}
 	return &resp, nil
 }
+
+func createPagedTestClient(pages map[string][]byte, requestURLs *[]string) Client {
+	fakeHTTPClient := PagedTestClient{pages: pages, requestURLs: requestURLs}
+	return Client{scheduler: newRequestScheduler(), apiToken: "irrelevant", apiEndpoint: DefaultAPIEndpoint, httpClient: &http.Client{Transport: fakeHTTPClient}}
+}
+
+// PagedTestClient responds with the body configured for the requested page
+// and 404 if there is none.
+type PagedTestClient struct {
+	pages       map[string][]byte
+	requestURLs *[]string
+}
+
+// See https://golang.org/pkg/net/http/#RoundTripper
+func (f PagedTestClient) RoundTrip(req *http.Request) (*http.Response, error) {
+	*f.requestURLs = append(*f.requestURLs, req.URL.String())
+
+	body, ok := f.pages[req.URL.Query().Get("page")]
+	if !ok {
+		return &http.Response{StatusCode: http.StatusNotFound, Body: ioutil.NopCloser(bytes.NewReader(nil))}, nil
+	}
+	return &http.Response{StatusCode: http.StatusOK, Body: ioutil.NopCloser(bytes.NewReader(body))}, nil
+}
+
+func TestClientCassetteZoneLifecycle(t *testing.T) {
+	client := createCassetteClient(t)
+	ctx := context.Background()
+
+	zone, err := client.CreateZone(ctx, CreateZoneOpts{Name: "cassette-zone.online", TTL: 60})
+	assert.NoError(t, err)
+	assert.Equal(t, "cassette-zone.online", zone.Name)
+
+	byName, err := client.GetZoneByName(ctx, "cassette-zone.online")
+	assert.NoError(t, err)
+	assert.Equal(t, zone.ID, byName.ID)
+
+	zone.TTL = 120
+	updated, err := client.UpdateZone(ctx, *zone)
+	assert.NoError(t, err)
+	assert.Equal(t, 120, updated.TTL)
+
+	assert.NoError(t, client.DeleteZone(ctx, zone.ID))
+	_, err = client.GetZone(ctx, zone.ID)
+	assert.True(t, errors.Is(err, ErrNotFound))
+}
+
+func TestClientCassetteRecordLifecycle(t *testing.T) {
+	client := createCassetteClient(t)
+	ctx := context.Background()
+
+	zone, err := client.CreateZone(ctx, CreateZoneOpts{Name: "cassette-records.online", TTL: 60})
+	assert.NoError(t, err)
+
+	ttl := 300
+	record, err := client.CreateRecord(ctx, CreateRecordOpts{ZoneID: zone.ID, Name: "www", Type: "A", Value: "192.0.2.1", TTL: &ttl})
+	assert.NoError(t, err)
+
+	records, err := client.ListRecords(ctx, zone.ID, ListRecordsOpts{})
+	assert.NoError(t, err)
+	assert.Contains(t, records, *record)
+
+	byName, err := client.GetRecordByName(ctx, zone.ID, "www", "A")
+	assert.NoError(t, err)
+	assert.Equal(t, record.ID, byName.ID)
+
+	record.Value = "192.0.2.2"
+	updated, err := client.UpdateRecord(ctx, *record)
+	assert.NoError(t, err)
+	assert.Equal(t, "192.0.2.2", updated.Value)
+
+	assert.NoError(t, client.DeleteRecord(ctx, record.ID))
+	assert.NoError(t, client.DeleteZone(ctx, zone.ID))
+}
+
+func TestClientCassettePrimaryServerLifecycle(t *testing.T) {
+	client := createCassetteClient(t)
+	ctx := context.Background()
+
+	zone, err := client.CreateZone(ctx, CreateZoneOpts{Name: "cassette-secondary.online", TTL: 60})
+	assert.NoError(t, err)
+
+	port := 53
+	primaryServer, err := client.CreatePrimaryServer(ctx, CreatePrimaryServerRequest{ZoneID: zone.ID, Address: "167.182.9.17", Port: &port})
+	assert.NoError(t, err)
+
+	primaryServers, err := client.ListPrimaryServers(ctx, zone.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, []PrimaryServer{*primaryServer}, primaryServers)
+
+	assert.NoError(t, client.DeletePrimaryServer(ctx, primaryServer.ID))
+	assert.NoError(t, client.DeleteZone(ctx, zone.ID))
+}
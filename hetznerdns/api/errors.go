@@ -0,0 +1,172 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Sentinel errors to check the kind of an error returned by the Client with errors.Is
+var (
+	ErrNotFound     = errors.New("not found")
+	ErrUnauthorized = errors.New("unauthorized")
+	ErrValidation   = errors.New("validation failed")
+	ErrRateLimited  = errors.New("rate limited")
+	ErrServer       = errors.New("server error")
+	// ErrMultipleMatches is returned by lookups which found several objects
+	// where exactly one was expected
+	ErrMultipleMatches = errors.New("multiple matches")
+)
+
+// APIError holds the details of a request the API responded to with an error.
+// It is returned as is for HTTP status codes without a more specific error type.
+type APIError struct {
+	StatusCode int
+	Method     string
+	Path       string
+	Body       string
+	Message    string
+	Code       int
+}
+
+func (e *APIError) Error() string {
+	msg := fmt.Sprintf("API returned HTTP %d %s error", e.StatusCode, http.StatusText(e.StatusCode))
+	if e.Message != "" {
+		msg = fmt.Sprintf("%s with message: '%s'", msg, e.Message)
+	}
+	if e.Method != "" {
+		msg = fmt.Sprintf("%s %s: %s", e.Method, e.Path, msg)
+	}
+	return msg
+}
+
+// NotFoundError is returned if the API responded with HTTP 404
+type NotFoundError struct {
+	APIError
+}
+
+// Is reports whether target is ErrNotFound
+func (e *NotFoundError) Is(target error) bool {
+	return target == ErrNotFound
+}
+
+// UnauthorizedError is returned if the API responded with HTTP 401
+type UnauthorizedError struct {
+	APIError
+}
+
+func (e *UnauthorizedError) Error() string {
+	return fmt.Sprintf("%s. Double check your API key is still valid", e.APIError.Error())
+}
+
+// Is reports whether target is ErrUnauthorized
+func (e *UnauthorizedError) Is(target error) bool {
+	return target == ErrUnauthorized
+}
+
+// ValidationError is returned if the API responded with HTTP 422
+type ValidationError struct {
+	APIError
+}
+
+// Is reports whether target is ErrValidation
+func (e *ValidationError) Is(target error) bool {
+	return target == ErrValidation
+}
+
+// RateLimitedError is returned if the API responded with HTTP 429
+type RateLimitedError struct {
+	APIError
+	// RetryAfter is the duration the API asked to wait before sending
+	// the next request. It is zero if the API didn't tell.
+	RetryAfter time.Duration
+}
+
+// Is reports whether target is ErrRateLimited
+func (e *RateLimitedError) Is(target error) bool {
+	return target == ErrRateLimited
+}
+
+// ServerError is returned if the API responded with HTTP 5xx
+type ServerError struct {
+	APIError
+}
+
+// Is reports whether target is ErrServer
+func (e *ServerError) Is(target error) bool {
+	return target == ErrServer
+}
+
+// errorResponse covers both shapes of error bodies returned by the API,
+// `{"message":"..."}` and `{"error":{"message":"...","code":422}}`.
+type errorResponse struct {
+	Message string       `json:"message"`
+	Error   ErrorMessage `json:"error"`
+}
+
+// ErrorMessage is the message of an error response
+type ErrorMessage struct {
+	Message string `json:"message"`
+	Code    int    `json:"code"`
+}
+
+// newErrorFromResponse reads the body of a response with a HTTP status
+// code >= 400 and returns the matching error type.
+func newErrorFromResponse(req *http.Request, resp *http.Response) error {
+	body, err := ioutil.ReadAll(resp.Body)
+	defer resp.Body.Close()
+	if err != nil {
+		return fmt.Errorf("Error reading HTTP response body: %w", err)
+	}
+
+	apiError := APIError{
+		StatusCode: resp.StatusCode,
+		Method:     req.Method,
+		Path:       req.URL.Path,
+		Body:       string(body),
+	}
+
+	var parsed errorResponse
+	if json.Unmarshal(body, &parsed) == nil {
+		apiError.Message = parsed.Message
+		apiError.Code = parsed.Error.Code
+		if parsed.Error.Message != "" {
+			apiError.Message = parsed.Error.Message
+		}
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return &NotFoundError{apiError}
+	case resp.StatusCode == http.StatusUnauthorized:
+		return &UnauthorizedError{apiError}
+	case resp.StatusCode == http.StatusUnprocessableEntity:
+		return &ValidationError{apiError}
+	case resp.StatusCode == http.StatusTooManyRequests:
+		return &RateLimitedError{APIError: apiError, RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After"))}
+	case resp.StatusCode >= http.StatusInternalServerError:
+		return &ServerError{apiError}
+	}
+	return &apiError
+}
+
+// parseRetryAfter parses the value of a Retry-After header, which is either
+// a number of seconds or a HTTP date.
+func parseRetryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		if wait := time.Until(date); wait > 0 {
+			return wait
+		}
+	}
+	return 0
+}
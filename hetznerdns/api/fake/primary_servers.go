@@ -0,0 +1,154 @@
+package fake
+
+import (
+	"net"
+	"net/http"
+	"sort"
+)
+
+// PrimaryServer is a primary server of a secondary zone as returned by the API
+type PrimaryServer struct {
+	ID       string `json:"id"`
+	Port     int    `json:"port"`
+	ZoneID   string `json:"zone_id"`
+	Address  string `json:"address"`
+	Created  string `json:"created"`
+	Modified string `json:"modified"`
+
+	seq int64
+}
+
+type primaryServerRequest struct {
+	Address string `json:"address"`
+	Port    *int   `json:"port"`
+	ZoneID  string `json:"zone_id"`
+}
+
+type primaryServerResponse struct {
+	PrimaryServer PrimaryServer `json:"primary_server"`
+}
+
+type primaryServersResponse struct {
+	PrimaryServers []PrimaryServer `json:"primary_servers"`
+}
+
+// PrimaryServers returns all primary servers of a zone ordered by creation
+func (s *Server) PrimaryServers(zoneID string) []PrimaryServer {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.primaryServersOfZone(zoneID)
+}
+
+func (s *Server) primaryServersOfZone(zoneID string) []PrimaryServer {
+	primaryServers := []PrimaryServer{}
+	for _, primaryServer := range s.primaryServers {
+		if primaryServer.ZoneID == zoneID {
+			primaryServers = append(primaryServers, *primaryServer)
+		}
+	}
+	sort.Slice(primaryServers, func(i, j int) bool { return primaryServers[i].seq < primaryServers[j].seq })
+	return primaryServers
+}
+
+// validatePrimaryServer returns an error message if the API would reject the primary server
+func (s *Server) validatePrimaryServer(req primaryServerRequest) string {
+	if _, ok := s.zones[req.ZoneID]; !ok {
+		return "zone not found"
+	}
+	ip := net.ParseIP(req.Address)
+	if ip == nil {
+		return "invalid address"
+	}
+	if ip.IsLoopback() || ip.IsUnspecified() {
+		return "address must be a public IP address"
+	}
+	if req.Port == nil || *req.Port < 1 || *req.Port > 65535 {
+		return "invalid port"
+	}
+	return ""
+}
+
+func (s *Server) listPrimaryServers(w http.ResponseWriter, r *http.Request) {
+	zoneID := r.URL.Query().Get("zone_id")
+	if _, ok := s.zones[zoneID]; !ok {
+		writeError(w, http.StatusNotFound, "zone not found")
+		return
+	}
+
+	primaryServers := s.primaryServersOfZone(zoneID)
+	if len(primaryServers) == 0 {
+		writeError(w, http.StatusNotFound, "primary servers not found")
+		return
+	}
+	writeJSON(w, http.StatusOK, primaryServersResponse{PrimaryServers: primaryServers})
+}
+
+func (s *Server) createPrimaryServer(w http.ResponseWriter, r *http.Request) {
+	var req primaryServerRequest
+	if err := readJSON(r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON body")
+		return
+	}
+	if msg := s.validatePrimaryServer(req); msg != "" {
+		writeError(w, http.StatusUnprocessableEntity, msg)
+		return
+	}
+
+	timestamp := now()
+	primaryServer := &PrimaryServer{
+		ID:       newID(),
+		Port:     *req.Port,
+		ZoneID:   req.ZoneID,
+		Address:  req.Address,
+		Created:  timestamp,
+		Modified: timestamp,
+		seq:      s.nextSeq(),
+	}
+	s.primaryServers[primaryServer.ID] = primaryServer
+	writeJSON(w, http.StatusOK, primaryServerResponse{PrimaryServer: *primaryServer})
+}
+
+func (s *Server) getPrimaryServer(w http.ResponseWriter, r *http.Request, id string) {
+	primaryServer, ok := s.primaryServers[id]
+	if !ok {
+		writeError(w, http.StatusNotFound, "primary server not found")
+		return
+	}
+	writeJSON(w, http.StatusOK, primaryServerResponse{PrimaryServer: *primaryServer})
+}
+
+func (s *Server) updatePrimaryServer(w http.ResponseWriter, r *http.Request, id string) {
+	primaryServer, ok := s.primaryServers[id]
+	if !ok {
+		writeError(w, http.StatusNotFound, "primary server not found")
+		return
+	}
+
+	var req primaryServerRequest
+	if err := readJSON(r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON body")
+		return
+	}
+	if req.ZoneID != primaryServer.ZoneID {
+		writeError(w, http.StatusUnprocessableEntity, "the zone of a primary server cannot be changed")
+		return
+	}
+	if msg := s.validatePrimaryServer(req); msg != "" {
+		writeError(w, http.StatusUnprocessableEntity, msg)
+		return
+	}
+
+	primaryServer.Address = req.Address
+	primaryServer.Port = *req.Port
+	primaryServer.Modified = now()
+	writeJSON(w, http.StatusOK, primaryServerResponse{PrimaryServer: *primaryServer})
+}
+
+func (s *Server) deletePrimaryServer(w http.ResponseWriter, r *http.Request, id string) {
+	if _, ok := s.primaryServers[id]; !ok {
+		writeError(w, http.StatusNotFound, "primary server not found")
+		return
+	}
+	delete(s.primaryServers, id)
+	w.WriteHeader(http.StatusOK)
+}
@@ -0,0 +1,336 @@
+package fake
+
+import (
+	"net"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// recordTypes are the record types supported by the API
+var recordTypes = map[string]bool{
+	"A":     true,
+	"AAAA":  true,
+	"CAA":   true,
+	"CNAME": true,
+	"DANE":  true,
+	"DS":    true,
+	"HINFO": true,
+	"MX":    true,
+	"NS":    true,
+	"PTR":   true,
+	"RP":    true,
+	"SOA":   true,
+	"SRV":   true,
+	"TLSA":  true,
+	"TXT":   true,
+}
+
+// Record is a DNS record as returned by the API
+type Record struct {
+	ID       string `json:"id"`
+	Type     string `json:"type"`
+	Name     string `json:"name"`
+	Value    string `json:"value"`
+	ZoneID   string `json:"zone_id"`
+	TTL      *int   `json:"ttl,omitempty"`
+	Created  string `json:"created"`
+	Modified string `json:"modified"`
+
+	seq int64
+}
+
+type recordRequest struct {
+	ID     string `json:"id,omitempty"`
+	ZoneID string `json:"zone_id"`
+	Type   string `json:"type"`
+	Name   string `json:"name"`
+	Value  string `json:"value"`
+	TTL    *int   `json:"ttl,omitempty"`
+}
+
+type recordResponse struct {
+	Record Record `json:"record"`
+}
+
+type recordsResponse struct {
+	Records []Record `json:"records"`
+	Meta    Meta     `json:"meta"`
+}
+
+type bulkRecordsRequest struct {
+	Records []recordRequest `json:"records"`
+}
+
+type bulkCreateRecordsResponse struct {
+	Records        []Record        `json:"records"`
+	ValidRecords   []Record        `json:"valid_records"`
+	InvalidRecords []recordRequest `json:"invalid_records"`
+}
+
+type bulkUpdateRecordsResponse struct {
+	Records       []Record        `json:"records"`
+	FailedRecords []recordRequest `json:"failed_records"`
+}
+
+// AddRecord creates a record without going through the HTTP API, e.g. to
+// set up a test. It returns the record as the API would return it.
+func (s *Server) AddRecord(zoneID string, name string, recordType string, value string, ttl *int) Record {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return *s.addRecord(zoneID, name, recordType, value, ttl)
+}
+
+// Records returns all records of a zone ordered by creation
+func (s *Server) Records(zoneID string) []Record {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	records := []Record{}
+	for _, record := range s.sortedRecords() {
+		if record.ZoneID == zoneID {
+			records = append(records, *record)
+		}
+	}
+	return records
+}
+
+// SetRecordValue changes the value of a record without going through the
+// HTTP API, e.g. to simulate a change outside of Terraform.
+func (s *Server) SetRecordValue(id string, value string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	record, ok := s.records[id]
+	if !ok {
+		return false
+	}
+	record.Value = value
+	record.Modified = now()
+	return true
+}
+
+// RemoveRecord deletes a record without going through the HTTP API, e.g.
+// to simulate a change outside of Terraform.
+func (s *Server) RemoveRecord(id string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.records[id]; !ok {
+		return false
+	}
+	delete(s.records, id)
+	return true
+}
+
+func (s *Server) addRecord(zoneID string, name string, recordType string, value string, ttl *int) *Record {
+	timestamp := now()
+	record := &Record{
+		ID:       newID(),
+		ZoneID:   zoneID,
+		Name:     name,
+		Type:     recordType,
+		Value:    value,
+		TTL:      copyInt(ttl),
+		Created:  timestamp,
+		Modified: timestamp,
+		seq:      s.nextSeq(),
+	}
+	s.records[record.ID] = record
+	return record
+}
+
+func (s *Server) sortedRecords() []*Record {
+	records := make([]*Record, 0, len(s.records))
+	for _, record := range s.records {
+		records = append(records, record)
+	}
+	sort.Slice(records, func(i, j int) bool { return records[i].seq < records[j].seq })
+	return records
+}
+
+// validateRecord returns an error message if the API would reject the record
+func (s *Server) validateRecord(req recordRequest) string {
+	if _, ok := s.zones[req.ZoneID]; !ok {
+		return "zone not found"
+	}
+	return validateRecordFields(req)
+}
+
+// validateRecordFields validates a record independent of its zone
+func validateRecordFields(req recordRequest) string {
+	if req.Name == "" {
+		return "name is required"
+	}
+	if !recordTypes[req.Type] {
+		return "invalid record type"
+	}
+	if req.Value == "" {
+		return "value is required"
+	}
+	if req.TTL != nil && *req.TTL < 0 {
+		return "invalid TTL"
+	}
+
+	switch req.Type {
+	case "A":
+		ip := net.ParseIP(req.Value)
+		if ip == nil || ip.To4() == nil {
+			return "invalid A record"
+		}
+	case "AAAA":
+		ip := net.ParseIP(req.Value)
+		if ip == nil || ip.To4() != nil {
+			return "invalid AAAA record"
+		}
+	case "CNAME", "NS":
+		if strings.ContainsAny(req.Value, " \t") {
+			return "invalid " + req.Type + " record"
+		}
+	}
+	return ""
+}
+
+func (s *Server) listRecords(w http.ResponseWriter, r *http.Request) {
+	zoneID := r.URL.Query().Get("zone_id")
+	if zoneID != "" {
+		if _, ok := s.zones[zoneID]; !ok {
+			writeError(w, http.StatusNotFound, "zone not found")
+			return
+		}
+	}
+
+	matches := []Record{}
+	for _, record := range s.sortedRecords() {
+		if zoneID == "" || record.ZoneID == zoneID {
+			matches = append(matches, *record)
+		}
+	}
+
+	start, end, meta, err := paginate(r, len(matches))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, recordsResponse{Records: matches[start:end], Meta: meta})
+}
+
+func (s *Server) createRecord(w http.ResponseWriter, r *http.Request) {
+	var req recordRequest
+	if err := readJSON(r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON body")
+		return
+	}
+	if msg := s.validateRecord(req); msg != "" {
+		writeError(w, http.StatusUnprocessableEntity, msg)
+		return
+	}
+
+	record := s.addRecord(req.ZoneID, req.Name, req.Type, req.Value, req.TTL)
+	writeJSON(w, http.StatusOK, recordResponse{Record: *record})
+}
+
+func (s *Server) getRecord(w http.ResponseWriter, r *http.Request, id string) {
+	record, ok := s.records[id]
+	if !ok {
+		writeError(w, http.StatusNotFound, "record not found")
+		return
+	}
+	writeJSON(w, http.StatusOK, recordResponse{Record: *record})
+}
+
+func (s *Server) updateRecord(w http.ResponseWriter, r *http.Request, id string) {
+	record, ok := s.records[id]
+	if !ok {
+		writeError(w, http.StatusNotFound, "record not found")
+		return
+	}
+
+	var req recordRequest
+	if err := readJSON(r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON body")
+		return
+	}
+	if msg := s.applyRecordUpdate(record, req); msg != "" {
+		writeError(w, http.StatusUnprocessableEntity, msg)
+		return
+	}
+	writeJSON(w, http.StatusOK, recordResponse{Record: *record})
+}
+
+// applyRecordUpdate validates req and updates record with it
+func (s *Server) applyRecordUpdate(record *Record, req recordRequest) string {
+	if req.ZoneID != record.ZoneID {
+		return "the zone of a record cannot be changed"
+	}
+	if msg := s.validateRecord(req); msg != "" {
+		return msg
+	}
+
+	record.Name = req.Name
+	record.Type = req.Type
+	record.Value = req.Value
+	record.TTL = copyInt(req.TTL)
+	record.Modified = now()
+	return ""
+}
+
+func (s *Server) deleteRecord(w http.ResponseWriter, r *http.Request, id string) {
+	if _, ok := s.records[id]; !ok {
+		writeError(w, http.StatusNotFound, "record not found")
+		return
+	}
+	delete(s.records, id)
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *Server) bulkCreateRecords(w http.ResponseWriter, r *http.Request) {
+	var req bulkRecordsRequest
+	if err := readJSON(r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON body")
+		return
+	}
+
+	response := bulkCreateRecordsResponse{Records: []Record{}, ValidRecords: []Record{}, InvalidRecords: []recordRequest{}}
+	for _, recordReq := range req.Records {
+		if msg := s.validateRecord(recordReq); msg != "" {
+			response.InvalidRecords = append(response.InvalidRecords, recordReq)
+			continue
+		}
+		record := s.addRecord(recordReq.ZoneID, recordReq.Name, recordReq.Type, recordReq.Value, recordReq.TTL)
+		response.Records = append(response.Records, *record)
+		response.ValidRecords = append(response.ValidRecords, *record)
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+func (s *Server) bulkUpdateRecords(w http.ResponseWriter, r *http.Request) {
+	var req bulkRecordsRequest
+	if err := readJSON(r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON body")
+		return
+	}
+
+	response := bulkUpdateRecordsResponse{Records: []Record{}, FailedRecords: []recordRequest{}}
+	for _, recordReq := range req.Records {
+		record, ok := s.records[recordReq.ID]
+		if !ok {
+			response.FailedRecords = append(response.FailedRecords, recordReq)
+			continue
+		}
+		if msg := s.applyRecordUpdate(record, recordReq); msg != "" {
+			response.FailedRecords = append(response.FailedRecords, recordReq)
+			continue
+		}
+		response.Records = append(response.Records, *record)
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+func copyInt(i *int) *int {
+	if i == nil {
+		return nil
+	}
+	c := *i
+	return &c
+}
@@ -0,0 +1,280 @@
+// Package fake provides an in-process fake of the Hetzner DNS API for tests.
+//
+// The fake keeps zones, records and primary servers in memory and implements
+// the endpoints used by the api.Client, including pagination, the bulk record
+// endpoints and zone file import, export and validation. It validates requests
+// and responds with the same error bodies as the real API, so tests can run
+// offline against it.
+package fake
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	apiPrefix = "/api/v1"
+	// timeFormat is the format the API uses for timestamps
+	timeFormat = "2006-01-02 15:04:05.000 -0700 MST"
+
+	defaultPerPage = 100
+	maxPerPage     = 100
+)
+
+// DefaultNameServers are the name servers assigned to every zone
+var DefaultNameServers = []string{"hydrogen.ns.hetzner.com", "oxygen.ns.hetzner.com", "helium.ns.hetzner.de"}
+
+// Server is a stateful fake of the Hetzner DNS API backed by a httptest.Server.
+type Server struct {
+	server   *httptest.Server
+	apiToken string
+
+	mu             sync.Mutex
+	zones          map[string]*Zone
+	records        map[string]*Record
+	primaryServers map[string]*PrimaryServer
+	requests       []string
+	// seq orders entities by creation, which is the order the API lists them in
+	seq int64
+}
+
+// NewServer starts a fake API which accepts requests authenticated with apiToken.
+// Call Close when done.
+func NewServer(apiToken string) *Server {
+	s := &Server{
+		apiToken:       apiToken,
+		zones:          map[string]*Zone{},
+		records:        map[string]*Record{},
+		primaryServers: map[string]*PrimaryServer{},
+	}
+	s.server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+// URL returns the base URL of the fake API, to be used as endpoint of api.NewClient
+func (s *Server) URL() string {
+	return s.server.URL + apiPrefix
+}
+
+// Close shuts down the fake API
+func (s *Server) Close() {
+	s.server.Close()
+}
+
+// Requests returns "METHOD /path" of every request received so far
+func (s *Server) Requests() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.requests...)
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.requests = append(s.requests, fmt.Sprintf("%s %s", r.Method, r.URL.Path))
+
+	if r.Header.Get("Auth-API-Token") != s.apiToken {
+		writeJSON(w, http.StatusUnauthorized, map[string]string{"message": "Invalid API key"})
+		return
+	}
+
+	if !strings.HasPrefix(r.URL.Path, apiPrefix+"/") {
+		writeError(w, http.StatusNotFound, "not found")
+		return
+	}
+	path := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, apiPrefix), "/"), "/")
+
+	switch {
+	case len(path) == 1 && path[0] == "zones":
+		s.routeCollection(w, r, s.listZones, s.createZone)
+	case len(path) == 3 && path[0] == "zones" && path[1] == "file" && path[2] == "validate":
+		s.routeMethod(w, r, http.MethodPost, s.validateZoneFile)
+	case len(path) == 2 && path[0] == "zones":
+		s.routeEntity(w, r, path[1], s.getZone, s.updateZone, s.deleteZone)
+	case len(path) == 3 && path[0] == "zones" && path[2] == "import":
+		s.routeMethod(w, r, http.MethodPost, func(w http.ResponseWriter, r *http.Request) { s.importZoneFile(w, r, path[1]) })
+	case len(path) == 3 && path[0] == "zones" && path[2] == "export":
+		s.routeMethod(w, r, http.MethodGet, func(w http.ResponseWriter, r *http.Request) { s.exportZoneFile(w, r, path[1]) })
+	case len(path) == 1 && path[0] == "records":
+		s.routeCollection(w, r, s.listRecords, s.createRecord)
+	case len(path) == 2 && path[0] == "records" && path[1] == "bulk":
+		switch r.Method {
+		case http.MethodPost:
+			s.bulkCreateRecords(w, r)
+		case http.MethodPut:
+			s.bulkUpdateRecords(w, r)
+		default:
+			writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		}
+	case len(path) == 2 && path[0] == "records":
+		s.routeEntity(w, r, path[1], s.getRecord, s.updateRecord, s.deleteRecord)
+	case len(path) == 1 && path[0] == "primary_servers":
+		s.routeCollection(w, r, s.listPrimaryServers, s.createPrimaryServer)
+	case len(path) == 2 && path[0] == "primary_servers":
+		s.routeEntity(w, r, path[1], s.getPrimaryServer, s.updatePrimaryServer, s.deletePrimaryServer)
+	default:
+		writeError(w, http.StatusNotFound, "not found")
+	}
+}
+
+type handlerFunc func(w http.ResponseWriter, r *http.Request)
+type entityHandlerFunc func(w http.ResponseWriter, r *http.Request, id string)
+
+func (s *Server) routeCollection(w http.ResponseWriter, r *http.Request, list handlerFunc, create handlerFunc) {
+	switch r.Method {
+	case http.MethodGet:
+		list(w, r)
+	case http.MethodPost:
+		create(w, r)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
+
+func (s *Server) routeEntity(w http.ResponseWriter, r *http.Request, id string, get, update, del entityHandlerFunc) {
+	switch r.Method {
+	case http.MethodGet:
+		get(w, r, id)
+	case http.MethodPut:
+		update(w, r, id)
+	case http.MethodDelete:
+		del(w, r, id)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
+
+func (s *Server) routeMethod(w http.ResponseWriter, r *http.Request, method string, handler handlerFunc) {
+	if r.Method != method {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	handler(w, r)
+}
+
+// errorBody is the body of all error responses except HTTP 401
+type errorBody struct {
+	Error errorMessage `json:"error"`
+}
+
+type errorMessage struct {
+	Message string `json:"message"`
+	Code    int    `json:"code"`
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, errorBody{Error: errorMessage{Message: fmt.Sprintf("%d : %s", status, message), Code: status}})
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
+
+func readJSON(r *http.Request, v interface{}) error {
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(body, v)
+}
+
+// Meta is the meta data of a paginated list response
+type Meta struct {
+	Pagination Pagination `json:"pagination"`
+}
+
+// Pagination describes the page of a list response
+type Pagination struct {
+	Page         int `json:"page"`
+	PerPage      int `json:"per_page"`
+	PreviousPage int `json:"previous_page"`
+	NextPage     int `json:"next_page"`
+	LastPage     int `json:"last_page"`
+	TotalEntries int `json:"total_entries"`
+}
+
+// paginate returns the bounds of the requested page of total entries and its meta data
+func paginate(r *http.Request, total int) (int, int, Meta, error) {
+	page, err := queryInt(r, "page", 1)
+	if err != nil || page < 1 {
+		return 0, 0, Meta{}, fmt.Errorf("invalid page")
+	}
+	perPage, err := queryInt(r, "per_page", defaultPerPage)
+	if err != nil || perPage < 1 || perPage > maxPerPage {
+		return 0, 0, Meta{}, fmt.Errorf("invalid per_page")
+	}
+
+	lastPage := (total + perPage - 1) / perPage
+	if lastPage == 0 {
+		lastPage = 1
+	}
+	start := (page - 1) * perPage
+	if start > total {
+		start = total
+	}
+	end := start + perPage
+	if end > total {
+		end = total
+	}
+
+	meta := Meta{Pagination: Pagination{
+		Page:         page,
+		PerPage:      perPage,
+		PreviousPage: maxInt(page-1, 1),
+		NextPage:     minInt(page+1, lastPage),
+		LastPage:     lastPage,
+		TotalEntries: total,
+	}}
+	return start, end, meta, nil
+}
+
+func queryInt(r *http.Request, name string, defaultValue int) (int, error) {
+	value := r.URL.Query().Get(name)
+	if value == "" {
+		return defaultValue, nil
+	}
+	return strconv.Atoi(value)
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func newID() string {
+	b := make([]byte, 11)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return hex.EncodeToString(b)
+}
+
+func (s *Server) nextSeq() int64 {
+	s.seq++
+	return s.seq
+}
+
+func now() string {
+	return time.Now().UTC().Format(timeFormat)
+}
@@ -0,0 +1,263 @@
+package fake_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/timohirt/terraform-provider-hetznerdns/hetznerdns/api"
+	"github.com/timohirt/terraform-provider-hetznerdns/hetznerdns/api/fake"
+)
+
+const testToken = "secret"
+
+func createClient(t *testing.T, server *fake.Server) *api.Client {
+	client, diags := api.NewClient(testToken, api.ClientOpts{Endpoint: server.URL()})
+	assert.False(t, diags.HasError())
+	return client
+}
+
+func doRequest(t *testing.T, server *fake.Server, method string, path string, body string) (int, string) {
+	req, err := http.NewRequest(method, server.URL()+path, strings.NewReader(body))
+	assert.NoError(t, err)
+	req.Header.Set("Auth-API-Token", testToken)
+
+	resp, err := http.DefaultClient.Do(req)
+	assert.NoError(t, err)
+	defer resp.Body.Close()
+	respBody, err := ioutil.ReadAll(resp.Body)
+	assert.NoError(t, err)
+	return resp.StatusCode, string(respBody)
+}
+
+func TestServerZoneLifecycle(t *testing.T) {
+	server := fake.NewServer(testToken)
+	defer server.Close()
+	client := createClient(t, server)
+	ctx := context.Background()
+
+	zone, err := client.CreateZone(ctx, api.CreateZoneOpts{Name: "mydomain.com", TTL: 3600})
+	assert.NoError(t, err)
+	assert.Equal(t, "mydomain.com", zone.Name)
+	assert.Equal(t, 3600, zone.TTL)
+	assert.Equal(t, fake.DefaultNameServers, zone.NS)
+	assert.Equal(t, "verified", zone.Status)
+	assert.Equal(t, 4, zone.RecordsCount)
+
+	zone.TTL = 7200
+	updated, err := client.UpdateZone(ctx, *zone)
+	assert.NoError(t, err)
+	assert.Equal(t, 7200, updated.TTL)
+
+	byName, err := client.GetZoneByName(ctx, "mydomain.com")
+	assert.NoError(t, err)
+	assert.Equal(t, zone.ID, byName.ID)
+
+	assert.NoError(t, client.DeleteZone(ctx, zone.ID))
+	_, err = client.GetZone(ctx, zone.ID)
+	assert.True(t, errors.Is(err, api.ErrNotFound))
+	_, err = client.GetZoneByName(ctx, "mydomain.com")
+	assert.True(t, errors.Is(err, api.ErrNotFound))
+}
+
+func TestServerRecordLifecycle(t *testing.T) {
+	server := fake.NewServer(testToken)
+	defer server.Close()
+	client := createClient(t, server)
+	ctx := context.Background()
+	zone := server.AddZone("mydomain.com", 3600)
+
+	ttl := 60
+	record, err := client.CreateRecord(ctx, api.CreateRecordOpts{ZoneID: zone.ID, Name: "www", Type: "A", Value: "192.0.2.1", TTL: &ttl})
+	assert.NoError(t, err)
+	assert.Equal(t, "www", record.Name)
+	assert.Equal(t, 60, *record.TTL)
+
+	record.Value = "192.0.2.2"
+	_, err = client.UpdateRecord(ctx, *record)
+	assert.NoError(t, err)
+
+	read, err := client.GetRecordByName(ctx, zone.ID, "www", "A")
+	assert.NoError(t, err)
+	assert.Equal(t, "192.0.2.2", read.Value)
+
+	assert.NoError(t, client.DeleteRecord(ctx, record.ID))
+	_, err = client.GetRecord(ctx, record.ID)
+	assert.True(t, errors.Is(err, api.ErrNotFound))
+
+	// Deleting the zone deletes its records as well
+	other := server.AddRecord(zone.ID, "mail", "A", "192.0.2.3", nil)
+	assert.True(t, server.RemoveZone(zone.ID))
+	_, err = client.GetRecord(ctx, other.ID)
+	assert.True(t, errors.Is(err, api.ErrNotFound))
+}
+
+func TestServerPagination(t *testing.T) {
+	server := fake.NewServer(testToken)
+	defer server.Close()
+	client := createClient(t, server)
+	ctx := context.Background()
+
+	for i := 0; i < 5; i++ {
+		server.AddZone(fmt.Sprintf("domain%d.com", i), 3600)
+	}
+	zone := server.Zones()[0]
+	for i := 0; i < 6; i++ {
+		server.AddRecord(zone.ID, fmt.Sprintf("host%d", i), "A", "192.0.2.1", nil)
+	}
+
+	zones, err := client.ListZones(ctx, api.ListZonesOpts{PerPage: 2})
+	assert.NoError(t, err)
+	assert.Len(t, zones, 5)
+	assert.Equal(t, "domain0.com", zones[0].Name)
+	assert.Equal(t, "domain4.com", zones[4].Name)
+
+	searched, err := client.ListZones(ctx, api.ListZonesOpts{SearchName: "domain3"})
+	assert.NoError(t, err)
+	assert.Len(t, searched, 1)
+
+	records, err := client.ListRecords(ctx, zone.ID, api.ListRecordsOpts{PerPage: 3})
+	assert.NoError(t, err)
+	// SOA and three NS records are created with every zone
+	assert.Len(t, records, 10)
+
+	var zonePages int
+	for _, request := range server.Requests() {
+		if request == "GET /api/v1/zones" {
+			zonePages++
+		}
+	}
+	assert.Equal(t, 3+1, zonePages)
+}
+
+func TestServerBulkRecords(t *testing.T) {
+	server := fake.NewServer(testToken)
+	defer server.Close()
+	client := createClient(t, server)
+	ctx := context.Background()
+	zone := server.AddZone("mydomain.com", 3600)
+
+	created, err := client.BulkCreateRecords(ctx, []api.CreateRecordOpts{
+		{ZoneID: zone.ID, Name: "www", Type: "A", Value: "192.0.2.1"},
+		{ZoneID: zone.ID, Name: "www", Type: "AAAA", Value: "not-an-ip"},
+	})
+	var bulkErr *api.BulkRecordsError
+	assert.True(t, errors.As(err, &bulkErr))
+	assert.Len(t, bulkErr.FailedRecords, 1)
+	assert.Len(t, created, 1)
+
+	created[0].Value = "192.0.2.2"
+	updated, err := client.BulkUpdateRecords(ctx, created)
+	assert.NoError(t, err)
+	assert.Equal(t, "192.0.2.2", updated[0].Value)
+}
+
+func TestServerZoneFile(t *testing.T) {
+	server := fake.NewServer(testToken)
+	defer server.Close()
+	client := createClient(t, server)
+	ctx := context.Background()
+	zone := server.AddZone("mydomain.com", 3600)
+
+	zoneFile := `$ORIGIN mydomain.com.
+$TTL 3600
+@	IN	SOA	hydrogen.ns.hetzner.com. dns.hetzner.com. (
+		2020010100 ; serial
+		86400 10800 3600000 3600 )
+@	IN	NS	hydrogen.ns.hetzner.com.
+www	60	IN	A	192.0.2.1
+	IN	AAAA	2001:db8::1
+mail.mydomain.com.	IN	TXT	"v=spf1 -all ; not a comment"
+`
+	validation, err := client.ValidateZoneFile(ctx, zoneFile)
+	assert.NoError(t, err)
+	assert.True(t, validation.IsValid())
+	assert.Equal(t, 5, validation.ParsedRecords)
+
+	imported, err := client.ImportZoneFile(ctx, zone.ID, zoneFile)
+	assert.NoError(t, err)
+	assert.Equal(t, 5, imported.RecordsCount)
+
+	records := server.Records(zone.ID)
+	assert.Equal(t, "www", records[3].Name)
+	assert.Equal(t, "AAAA", records[3].Type)
+	assert.Equal(t, "mail", records[4].Name)
+	assert.Equal(t, `"v=spf1 -all ; not a comment"`, records[4].Value)
+
+	exported, err := client.ExportZoneFile(ctx, zone.ID)
+	assert.NoError(t, err)
+	assert.Contains(t, exported, "$ORIGIN mydomain.com.\n")
+	assert.Contains(t, exported, "www\t60\tIN\tA\t192.0.2.1\n")
+
+	invalid, err := client.ValidateZoneFile(ctx, "www IN A 2001:db8::1\n")
+	assert.NoError(t, err)
+	assert.False(t, invalid.IsValid())
+}
+
+func TestServerPrimaryServers(t *testing.T) {
+	server := fake.NewServer(testToken)
+	defer server.Close()
+	client := createClient(t, server)
+	ctx := context.Background()
+	zone := server.AddZone("mydomain.com", 3600)
+
+	primaryServers, err := client.ListPrimaryServers(ctx, zone.ID)
+	assert.NoError(t, err)
+	assert.Empty(t, primaryServers)
+
+	_, err = client.ListPrimaryServers(ctx, "missing")
+	assert.True(t, errors.Is(err, api.ErrNotFound))
+
+	port := 53
+	primaryServer, err := client.CreatePrimaryServer(ctx, api.CreatePrimaryServerRequest{ZoneID: zone.ID, Address: "192.0.2.1", Port: &port})
+	assert.NoError(t, err)
+	assert.True(t, server.Zones()[0].IsSecondaryDNS)
+
+	primaryServers, err = client.ListPrimaryServers(ctx, zone.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, []api.PrimaryServer{*primaryServer}, primaryServers)
+
+	assert.NoError(t, client.DeletePrimaryServer(ctx, primaryServer.ID))
+	_, err = client.GetPrimaryServer(ctx, primaryServer.ID)
+	assert.True(t, errors.Is(err, api.ErrNotFound))
+}
+
+func TestServerRejectsInvalidAPIToken(t *testing.T) {
+	server := fake.NewServer(testToken)
+	defer server.Close()
+	client, _ := api.NewClient("invalid", api.ClientOpts{Endpoint: server.URL()})
+
+	_, err := client.ListZones(context.Background(), api.ListZonesOpts{})
+	assert.True(t, errors.Is(err, api.ErrUnauthorized))
+}
+
+func TestServerValidation(t *testing.T) {
+	server := fake.NewServer(testToken)
+	defer server.Close()
+	zone := server.AddZone("mydomain.com", 3600)
+
+	tests := []struct {
+		path string
+		body string
+	}{
+		{"/zones", `{"name":"mydomain.com"}`},
+		{"/zones", `{"name":"example.test"}`},
+		{"/zones", `{"name":"sub.mydomain.com"}`},
+		{"/records", fmt.Sprintf(`{"zone_id":"%s","name":"www","type":"A","value":"2001:db8::1"}`, zone.ID)},
+		{"/records", fmt.Sprintf(`{"zone_id":"%s","name":"www","type":"UNKNOWN","value":"x"}`, zone.ID)},
+		{"/records", `{"zone_id":"unknown","name":"www","type":"A","value":"192.0.2.1"}`},
+		{"/primary_servers", fmt.Sprintf(`{"zone_id":"%s","address":"127.0.0.1","port":53}`, zone.ID)},
+		{"/primary_servers", fmt.Sprintf(`{"zone_id":"%s","address":"192.0.2.1","port":70000}`, zone.ID)},
+		{"/zones/" + zone.ID + "/import", "www IN A ( 192.0.2.1"},
+	}
+	for _, test := range tests {
+		status, body := doRequest(t, server, http.MethodPost, test.path, test.body)
+		assert.Equal(t, http.StatusUnprocessableEntity, status, test.body)
+		assert.Contains(t, body, `"code":422`, test.body)
+	}
+}
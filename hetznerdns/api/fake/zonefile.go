@@ -0,0 +1,272 @@
+package fake
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+type zoneFileValidationResponse struct {
+	ParsedRecords  int             `json:"parsed_records"`
+	ValidRecords   []recordRequest `json:"valid_records"`
+	InvalidRecords []recordRequest `json:"invalid_records"`
+}
+
+// parseZoneFile parses the subset of the BIND zone file format the API
+// exports: $ORIGIN and $TTL directives, comments, parentheses spanning
+// several lines, and records of the form `name [ttl] [IN] type value`.
+func parseZoneFile(zoneFile string, zoneName string) ([]recordRequest, error) {
+	origin := zoneName + "."
+	var records []recordRequest
+	lastName := "@"
+
+	lines, err := logicalLines(zoneFile)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, line := range lines {
+		fields := splitFields(line.text)
+		if len(fields) == 0 {
+			continue
+		}
+
+		switch strings.ToUpper(fields[0]) {
+		case "$ORIGIN":
+			if len(fields) != 2 {
+				return nil, fmt.Errorf("line %d: invalid $ORIGIN", line.number)
+			}
+			origin = fields[1]
+			continue
+		case "$TTL":
+			if len(fields) != 2 {
+				return nil, fmt.Errorf("line %d: invalid $TTL", line.number)
+			}
+			continue
+		}
+
+		name := lastName
+		if !line.continuesName {
+			name = relativeName(fields[0], origin)
+			fields = fields[1:]
+		}
+		lastName = name
+
+		var ttl *int
+		if len(fields) > 0 {
+			if i, err := strconv.Atoi(fields[0]); err == nil {
+				ttl = &i
+				fields = fields[1:]
+			}
+		}
+		if len(fields) > 0 && strings.ToUpper(fields[0]) == "IN" {
+			fields = fields[1:]
+		}
+		if len(fields) < 2 {
+			return nil, fmt.Errorf("line %d: record without type or value", line.number)
+		}
+
+		records = append(records, recordRequest{
+			Name:  name,
+			Type:  strings.ToUpper(fields[0]),
+			Value: strings.Join(fields[1:], " "),
+			TTL:   ttl,
+		})
+	}
+	return records, nil
+}
+
+type logicalLine struct {
+	number int
+	text   string
+	// continuesName is true if the line starts with whitespace and thus
+	// belongs to the name of the previous record
+	continuesName bool
+}
+
+// logicalLines strips comments and joins lines enclosed in parentheses
+func logicalLines(zoneFile string) ([]logicalLine, error) {
+	var lines []logicalLine
+	var current *logicalLine
+	depth := 0
+
+	for i, raw := range strings.Split(zoneFile, "\n") {
+		text := stripComment(strings.TrimRight(raw, "\r"))
+		if current == nil {
+			if strings.TrimSpace(text) == "" {
+				continue
+			}
+			current = &logicalLine{number: i + 1, continuesName: text[0] == ' ' || text[0] == '\t'}
+		}
+
+		depth += strings.Count(text, "(") - strings.Count(text, ")")
+		text = strings.NewReplacer("(", " ", ")", " ").Replace(text)
+		current.text += " " + text
+
+		if depth < 0 {
+			return nil, fmt.Errorf("line %d: unbalanced parentheses", i+1)
+		}
+		if depth == 0 {
+			lines = append(lines, *current)
+			current = nil
+		}
+	}
+	if current != nil {
+		return nil, fmt.Errorf("line %d: unbalanced parentheses", current.number)
+	}
+	return lines, nil
+}
+
+// stripComment removes a comment started by ; outside of a quoted string
+func stripComment(line string) string {
+	quoted := false
+	for i, c := range line {
+		switch c {
+		case '"':
+			quoted = !quoted
+		case ';':
+			if !quoted {
+				return line[:i]
+			}
+		}
+	}
+	return line
+}
+
+// splitFields splits a line at whitespace but keeps quoted strings intact
+func splitFields(line string) []string {
+	var fields []string
+	var field strings.Builder
+	quoted := false
+	for _, c := range line {
+		switch {
+		case c == '"':
+			quoted = !quoted
+			field.WriteRune(c)
+		case (c == ' ' || c == '\t') && !quoted:
+			if field.Len() > 0 {
+				fields = append(fields, field.String())
+				field.Reset()
+			}
+		default:
+			field.WriteRune(c)
+		}
+	}
+	if field.Len() > 0 {
+		fields = append(fields, field.String())
+	}
+	return fields
+}
+
+// relativeName converts a record name to the relative form used by the API
+func relativeName(name string, origin string) string {
+	if name == "@" || name == origin {
+		return "@"
+	}
+	if strings.HasSuffix(name, "."+origin) {
+		return strings.TrimSuffix(name, "."+origin)
+	}
+	return strings.TrimSuffix(name, ".")
+}
+
+// renderZoneFile renders the records of a zone in BIND format
+func (s *Server) renderZoneFile(zone *Zone) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "$ORIGIN %s.\n", zone.Name)
+	fmt.Fprintf(&b, "$TTL %d\n", zone.TTL)
+	for _, record := range s.sortedRecords() {
+		if record.ZoneID != zone.ID {
+			continue
+		}
+		ttl := ""
+		if record.TTL != nil {
+			ttl = strconv.Itoa(*record.TTL)
+		}
+		fmt.Fprintf(&b, "%s\t%s\tIN\t%s\t%s\n", record.Name, ttl, record.Type, record.Value)
+	}
+	return b.String()
+}
+
+func readText(r *http.Request) (string, error) {
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return "", err
+	}
+	return string(body), nil
+}
+
+func (s *Server) importZoneFile(w http.ResponseWriter, r *http.Request, zoneID string) {
+	zone, ok := s.zones[zoneID]
+	if !ok {
+		writeError(w, http.StatusNotFound, "zone not found")
+		return
+	}
+
+	zoneFile, err := readText(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid body")
+		return
+	}
+	records, err := parseZoneFile(zoneFile, zone.Name)
+	if err != nil {
+		writeError(w, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+	for _, record := range records {
+		if msg := validateRecordFields(record); msg != "" {
+			writeError(w, http.StatusUnprocessableEntity, fmt.Sprintf("%s: %s %s %s", msg, record.Name, record.Type, record.Value))
+			return
+		}
+	}
+
+	for id, record := range s.records {
+		if record.ZoneID == zoneID {
+			delete(s.records, id)
+		}
+	}
+	for _, record := range records {
+		s.addRecord(zoneID, record.Name, record.Type, record.Value, record.TTL)
+	}
+	zone.Modified = now()
+
+	writeJSON(w, http.StatusOK, zoneResponse{Zone: s.view(zone)})
+}
+
+func (s *Server) exportZoneFile(w http.ResponseWriter, r *http.Request, zoneID string) {
+	zone, ok := s.zones[zoneID]
+	if !ok {
+		writeError(w, http.StatusNotFound, "zone not found")
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(s.renderZoneFile(zone)))
+}
+
+func (s *Server) validateZoneFile(w http.ResponseWriter, r *http.Request) {
+	zoneFile, err := readText(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid body")
+		return
+	}
+
+	// Without a zone, names are relative to the $ORIGIN of the file
+	records, err := parseZoneFile(zoneFile, "")
+	if err != nil {
+		writeError(w, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+
+	response := zoneFileValidationResponse{ParsedRecords: len(records), ValidRecords: []recordRequest{}, InvalidRecords: []recordRequest{}}
+	for _, record := range records {
+		if validateRecordFields(record) == "" {
+			response.ValidRecords = append(response.ValidRecords, record)
+		} else {
+			response.InvalidRecords = append(response.InvalidRecords, record)
+		}
+	}
+	writeJSON(w, http.StatusOK, response)
+}
@@ -0,0 +1,293 @@
+package fake
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+)
+
+const defaultZoneTTL = 86400
+
+// reservedTLDs can never be registered, see RFC 2606 and RFC 6761
+var reservedTLDs = map[string]bool{
+	"example":   true,
+	"invalid":   true,
+	"local":     true,
+	"localhost": true,
+	"test":      true,
+}
+
+// Zone is a DNS zone as returned by the API
+type Zone struct {
+	ID              string          `json:"id"`
+	Name            string          `json:"name"`
+	TTL             int             `json:"ttl"`
+	Registrar       string          `json:"registrar"`
+	LegacyDNSHost   string          `json:"legacy_dns_host"`
+	LegacyNS        []string        `json:"legacy_ns"`
+	NS              []string        `json:"ns"`
+	Created         string          `json:"created"`
+	Verified        string          `json:"verified"`
+	Modified        string          `json:"modified"`
+	Project         string          `json:"project"`
+	Owner           string          `json:"owner"`
+	Permission      string          `json:"permission"`
+	Status          string          `json:"status"`
+	Paused          bool            `json:"paused"`
+	IsSecondaryDNS  bool            `json:"is_secondary_dns"`
+	TxtVerification TxtVerification `json:"txt_verification"`
+	RecordsCount    int             `json:"records_count"`
+
+	seq int64
+}
+
+// TxtVerification is the TXT record which proves the ownership of a zone
+type TxtVerification struct {
+	Name  string `json:"name"`
+	Token string `json:"token"`
+}
+
+type zoneRequest struct {
+	Name string `json:"name"`
+	TTL  *int   `json:"ttl"`
+}
+
+type zoneResponse struct {
+	Zone Zone `json:"zone"`
+}
+
+type zonesResponse struct {
+	Zones []Zone `json:"zones"`
+	Meta  Meta   `json:"meta"`
+}
+
+// AddZone creates a zone without going through the HTTP API, e.g. to set
+// up a test. It returns the zone as the API would return it.
+func (s *Server) AddZone(name string, ttl int) Zone {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.view(s.addZone(name, ttl))
+}
+
+// Zones returns all zones ordered by creation
+func (s *Server) Zones() []Zone {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	zones := s.sortedZones()
+	views := make([]Zone, len(zones))
+	for i, zone := range zones {
+		views[i] = s.view(zone)
+	}
+	return views
+}
+
+// RemoveZone deletes a zone, its records and primary servers without going
+// through the HTTP API, e.g. to simulate a change outside of Terraform.
+func (s *Server) RemoveZone(id string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.removeZone(id)
+}
+
+func (s *Server) addZone(name string, ttl int) *Zone {
+	timestamp := now()
+	zone := &Zone{
+		ID:              newID(),
+		Name:            name,
+		TTL:             ttl,
+		LegacyNS:        []string{},
+		NS:              append([]string(nil), DefaultNameServers...),
+		Created:         timestamp,
+		Verified:        timestamp,
+		Modified:        timestamp,
+		Status:          "verified",
+		TxtVerification: TxtVerification{Name: "_hetzner", Token: newID()},
+		seq:             s.nextSeq(),
+	}
+	s.zones[zone.ID] = zone
+
+	// Like the real API, every new zone has a SOA record and the NS records
+	// of the default name servers.
+	s.addRecord(zone.ID, "@", "SOA", strings.Join([]string{DefaultNameServers[0] + ".", "dns.hetzner.com.", "2020010100", "86400", "10800", "3600000", "3600"}, " "), nil)
+	for _, ns := range DefaultNameServers {
+		s.addRecord(zone.ID, "@", "NS", ns+".", nil)
+	}
+	return zone
+}
+
+func (s *Server) removeZone(id string) bool {
+	if _, ok := s.zones[id]; !ok {
+		return false
+	}
+	delete(s.zones, id)
+	for recordID, record := range s.records {
+		if record.ZoneID == id {
+			delete(s.records, recordID)
+		}
+	}
+	for primaryServerID, primaryServer := range s.primaryServers {
+		if primaryServer.ZoneID == id {
+			delete(s.primaryServers, primaryServerID)
+		}
+	}
+	return true
+}
+
+// view returns a copy of a zone with all derived attributes set
+func (s *Server) view(zone *Zone) Zone {
+	view := *zone
+	view.RecordsCount = 0
+	for _, record := range s.records {
+		if record.ZoneID == zone.ID {
+			view.RecordsCount++
+		}
+	}
+	view.IsSecondaryDNS = false
+	for _, primaryServer := range s.primaryServers {
+		if primaryServer.ZoneID == zone.ID {
+			view.IsSecondaryDNS = true
+		}
+	}
+	return view
+}
+
+func (s *Server) sortedZones() []*Zone {
+	zones := make([]*Zone, 0, len(s.zones))
+	for _, zone := range s.zones {
+		zones = append(zones, zone)
+	}
+	sort.Slice(zones, func(i, j int) bool { return zones[i].seq < zones[j].seq })
+	return zones
+}
+
+func (s *Server) zoneByName(name string) *Zone {
+	for _, zone := range s.zones {
+		if zone.Name == name {
+			return zone
+		}
+	}
+	return nil
+}
+
+// validateZoneName returns an error message if the API would reject name
+func validateZoneName(name string) string {
+	labels := strings.Split(name, ".")
+	if len(labels) < 2 {
+		return "invalid domain"
+	}
+	for _, label := range labels {
+		if label == "" {
+			return "invalid domain"
+		}
+	}
+	if reservedTLDs[labels[len(labels)-1]] {
+		return "invalid TLD"
+	}
+	if len(labels) > 2 {
+		return "subdomains are not allowed, use a record instead"
+	}
+	return ""
+}
+
+func (s *Server) listZones(w http.ResponseWriter, r *http.Request) {
+	name := r.URL.Query().Get("name")
+	searchName := r.URL.Query().Get("search_name")
+
+	matches := []Zone{}
+	for _, zone := range s.sortedZones() {
+		if name != "" && zone.Name != name {
+			continue
+		}
+		if searchName != "" && !strings.Contains(zone.Name, searchName) {
+			continue
+		}
+		matches = append(matches, s.view(zone))
+	}
+
+	if name != "" && len(matches) == 0 {
+		writeError(w, http.StatusNotFound, "zone not found")
+		return
+	}
+
+	start, end, meta, err := paginate(r, len(matches))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, zonesResponse{Zones: matches[start:end], Meta: meta})
+}
+
+func (s *Server) createZone(w http.ResponseWriter, r *http.Request) {
+	var req zoneRequest
+	if err := readJSON(r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON body")
+		return
+	}
+
+	if msg := validateZoneName(req.Name); msg != "" {
+		writeError(w, http.StatusUnprocessableEntity, msg)
+		return
+	}
+	if s.zoneByName(req.Name) != nil {
+		writeError(w, http.StatusUnprocessableEntity, "zone already exists")
+		return
+	}
+
+	ttl := defaultZoneTTL
+	if req.TTL != nil {
+		if *req.TTL < 0 {
+			writeError(w, http.StatusUnprocessableEntity, "invalid TTL")
+			return
+		}
+		ttl = *req.TTL
+	}
+
+	zone := s.addZone(req.Name, ttl)
+	writeJSON(w, http.StatusOK, zoneResponse{Zone: s.view(zone)})
+}
+
+func (s *Server) getZone(w http.ResponseWriter, r *http.Request, id string) {
+	zone, ok := s.zones[id]
+	if !ok {
+		writeError(w, http.StatusNotFound, "zone not found")
+		return
+	}
+	writeJSON(w, http.StatusOK, zoneResponse{Zone: s.view(zone)})
+}
+
+func (s *Server) updateZone(w http.ResponseWriter, r *http.Request, id string) {
+	zone, ok := s.zones[id]
+	if !ok {
+		writeError(w, http.StatusNotFound, "zone not found")
+		return
+	}
+
+	var req zoneRequest
+	if err := readJSON(r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON body")
+		return
+	}
+	if req.Name != zone.Name {
+		writeError(w, http.StatusUnprocessableEntity, "the name of a zone cannot be changed")
+		return
+	}
+	if req.TTL != nil {
+		if *req.TTL < 0 {
+			writeError(w, http.StatusUnprocessableEntity, "invalid TTL")
+			return
+		}
+		zone.TTL = *req.TTL
+	}
+	zone.Modified = now()
+
+	writeJSON(w, http.StatusOK, zoneResponse{Zone: s.view(zone)})
+}
+
+func (s *Server) deleteZone(w http.ResponseWriter, r *http.Request, id string) {
+	if !s.removeZone(id) {
+		writeError(w, http.StatusNotFound, "zone not found")
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
@@ -0,0 +1,742 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"os"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/timohirt/terraform-provider-hetznerdns/hetznerdns/api/fake"
+)
+
+// fault is injected into the response of a single request attempt
+type fault struct {
+	// status responds with this HTTP status and body instead of sending the request
+	status int
+	body   string
+	header http.Header
+	// err fails the attempt with this error instead of sending the request
+	err error
+	// delay sends the request after waiting for this duration
+	delay time.Duration
+	// truncate cuts the response body of the request in half
+	truncate bool
+	// lose sends the request but fails the attempt as if the connection
+	// broke before the response arrived
+	lose bool
+}
+
+func statusFault(status int) fault {
+	return fault{status: status, body: `{"error":{"message":"injected fault","code":0}}`}
+}
+
+func rateLimitFault(retryAfter string) fault {
+	f := statusFault(http.StatusTooManyRequests)
+	f.header = http.Header{"Retry-After": []string{retryAfter}}
+	return f
+}
+
+func connectionResetFault() fault {
+	return fault{err: &net.OpError{Op: "read", Net: "tcp", Err: os.NewSyscallError("read", syscall.ECONNRESET)}}
+}
+
+func lostResponseFault() fault {
+	f := connectionResetFault()
+	f.lose = true
+	return f
+}
+
+// faultInjector is a RoundTripper which injects faults into the next
+// request attempts and records when every attempt was made. Only requests
+// with the method and path of the first request are faulted and recorded,
+// so lookups the client sends before retrying a create pass through.
+type faultInjector struct {
+	next http.RoundTripper
+
+	mu       sync.Mutex
+	faults   []fault
+	method   string
+	path     string
+	attempts []time.Time
+}
+
+func (f *faultInjector) inject(faults ...fault) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.faults = append(f.faults, faults...)
+}
+
+func (f *faultInjector) attemptTimes() []time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]time.Time(nil), f.attempts...)
+}
+
+func (f *faultInjector) RoundTrip(req *http.Request) (*http.Response, error) {
+	f.mu.Lock()
+	if f.method == "" {
+		f.method = req.Method
+		f.path = req.URL.Path
+	}
+	if req.Method != f.method || req.URL.Path != f.path {
+		f.mu.Unlock()
+		return f.next.RoundTrip(req)
+	}
+	f.attempts = append(f.attempts, time.Now())
+	var current fault
+	injected := len(f.faults) > 0
+	if injected {
+		current = f.faults[0]
+		f.faults = f.faults[1:]
+	}
+	f.mu.Unlock()
+
+	if !injected {
+		return f.next.RoundTrip(req)
+	}
+	if current.lose {
+		resp, err := f.next.RoundTrip(req)
+		if err == nil {
+			resp.Body.Close()
+		}
+		return nil, current.err
+	}
+	if current.err != nil {
+		return nil, current.err
+	}
+	if current.status != 0 {
+		header := http.Header{"Content-Type": []string{jsonContentType}}
+		for name, values := range current.header {
+			header[name] = values
+		}
+		return &http.Response{
+			Status:     http.StatusText(current.status),
+			StatusCode: current.status,
+			Header:     header,
+			Body:       ioutil.NopCloser(bytes.NewBufferString(current.body)),
+			Request:    req,
+		}, nil
+	}
+	if current.delay > 0 {
+		if err := sleepContext(req.Context(), current.delay); err != nil {
+			return nil, err
+		}
+	}
+
+	resp, err := f.next.RoundTrip(req)
+	if err != nil || !current.truncate {
+		return resp, err
+	}
+	body, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = ioutil.NopCloser(bytes.NewReader(body[:len(body)/2]))
+	return resp, nil
+}
+
+var testRetryConfig = retryConfig{
+	maxRetries: 3,
+	waitMin:    10 * time.Millisecond,
+	waitMax:    40 * time.Millisecond,
+}
+
+// faultTestSetup is a fake API with a zone, a record and a primary server and
+// a client which sends its requests through a faultInjector.
+type faultTestSetup struct {
+	server        *fake.Server
+	client        *Client
+	injector      *faultInjector
+	zone          fake.Zone
+	record        fake.Record
+	primaryServer PrimaryServer
+}
+
+func createFaultTestSetup(t *testing.T) faultTestSetup {
+	server := fake.NewServer("token")
+	t.Cleanup(server.Close)
+
+	zone := server.AddZone("faults.online", 3600)
+	record := server.AddRecord(zone.ID, "www", "A", "192.0.2.1", nil)
+
+	client, diags := NewClient("token", ClientOpts{Endpoint: server.URL()})
+	assert.False(t, diags.HasError())
+	port := 53
+	primaryServer, err := client.CreatePrimaryServer(context.Background(), CreatePrimaryServerRequest{ZoneID: zone.ID, Address: "192.0.2.53", Port: &port})
+	assert.NoError(t, err)
+
+	injector := &faultInjector{next: newDefaultTransport()}
+	client.httpClient = newHTTPClient(injector, client.scheduler, testRetryConfig)
+	return faultTestSetup{server: server, client: client, injector: injector, zone: zone, record: record, primaryServer: *primaryServer}
+}
+
+// clientMethod calls a method of the Client which sends a single request
+type clientMethod struct {
+	name string
+	call func(ctx context.Context, s faultTestSetup) error
+	// parsesJSON is true if the method parses a JSON response body
+	parsesJSON bool
+}
+
+var clientMethods = []clientMethod{
+	{"GetZone", func(ctx context.Context, s faultTestSetup) error {
+		_, err := s.client.GetZone(ctx, s.zone.ID)
+		return err
+	}, true},
+	{"GetZoneByName", func(ctx context.Context, s faultTestSetup) error {
+		_, err := s.client.GetZoneByName(ctx, s.zone.Name)
+		return err
+	}, true},
+	{"ListZones", func(ctx context.Context, s faultTestSetup) error {
+		_, err := s.client.ListZones(ctx, ListZonesOpts{})
+		return err
+	}, true},
+	{"CreateZone", func(ctx context.Context, s faultTestSetup) error {
+		_, err := s.client.CreateZone(ctx, CreateZoneOpts{Name: "another.online", TTL: 60})
+		return err
+	}, true},
+	{"UpdateZone", func(ctx context.Context, s faultTestSetup) error {
+		_, err := s.client.UpdateZone(ctx, Zone{ID: s.zone.ID, Name: s.zone.Name, TTL: 60})
+		return err
+	}, true},
+	{"DeleteZone", func(ctx context.Context, s faultTestSetup) error {
+		return s.client.DeleteZone(ctx, s.zone.ID)
+	}, false},
+	{"ImportZoneFile", func(ctx context.Context, s faultTestSetup) error {
+		_, err := s.client.ImportZoneFile(ctx, s.zone.ID, "www IN A 192.0.2.2\n")
+		return err
+	}, true},
+	{"ExportZoneFile", func(ctx context.Context, s faultTestSetup) error {
+		_, err := s.client.ExportZoneFile(ctx, s.zone.ID)
+		return err
+	}, false},
+	{"ValidateZoneFile", func(ctx context.Context, s faultTestSetup) error {
+		_, err := s.client.ValidateZoneFile(ctx, "www IN A 192.0.2.2\n")
+		return err
+	}, true},
+	{"ListRecords", func(ctx context.Context, s faultTestSetup) error {
+		_, err := s.client.ListRecords(ctx, s.zone.ID, ListRecordsOpts{})
+		return err
+	}, true},
+	{"GetRecordByName", func(ctx context.Context, s faultTestSetup) error {
+		_, err := s.client.GetRecordByName(ctx, s.zone.ID, s.record.Name, s.record.Type)
+		return err
+	}, true},
+	{"GetRecord", func(ctx context.Context, s faultTestSetup) error {
+		_, err := s.client.GetRecord(ctx, s.record.ID)
+		return err
+	}, true},
+	{"CreateRecord", func(ctx context.Context, s faultTestSetup) error {
+		_, err := s.client.CreateRecord(ctx, CreateRecordOpts{ZoneID: s.zone.ID, Name: "mail", Type: "A", Value: "192.0.2.2"})
+		return err
+	}, true},
+	{"UpdateRecord", func(ctx context.Context, s faultTestSetup) error {
+		_, err := s.client.UpdateRecord(ctx, Record{ID: s.record.ID, ZoneID: s.zone.ID, Name: "www", Type: "A", Value: "192.0.2.2"})
+		return err
+	}, true},
+	{"DeleteRecord", func(ctx context.Context, s faultTestSetup) error {
+		return s.client.DeleteRecord(ctx, s.record.ID)
+	}, false},
+	{"BulkCreateRecords", func(ctx context.Context, s faultTestSetup) error {
+		_, err := s.client.BulkCreateRecords(ctx, []CreateRecordOpts{{ZoneID: s.zone.ID, Name: "mail", Type: "A", Value: "192.0.2.2"}})
+		return err
+	}, true},
+	{"BulkUpdateRecords", func(ctx context.Context, s faultTestSetup) error {
+		_, err := s.client.BulkUpdateRecords(ctx, []Record{{ID: s.record.ID, ZoneID: s.zone.ID, Name: "www", Type: "A", Value: "192.0.2.2"}})
+		return err
+	}, true},
+	{"GetPrimaryServer", func(ctx context.Context, s faultTestSetup) error {
+		_, err := s.client.GetPrimaryServer(ctx, s.primaryServer.ID)
+		return err
+	}, true},
+	{"ListPrimaryServers", func(ctx context.Context, s faultTestSetup) error {
+		_, err := s.client.ListPrimaryServers(ctx, s.zone.ID)
+		return err
+	}, true},
+	{"CreatePrimaryServer", func(ctx context.Context, s faultTestSetup) error {
+		port := 53
+		_, err := s.client.CreatePrimaryServer(ctx, CreatePrimaryServerRequest{ZoneID: s.zone.ID, Address: "192.0.2.54", Port: &port})
+		return err
+	}, true},
+	{"UpdatePrimaryServer", func(ctx context.Context, s faultTestSetup) error {
+		port := 5353
+		primaryServer := s.primaryServer
+		primaryServer.Port = &port
+		_, err := s.client.UpdatePrimaryServer(ctx, primaryServer)
+		return err
+	}, true},
+	{"DeletePrimaryServer", func(ctx context.Context, s faultTestSetup) error {
+		return s.client.DeletePrimaryServer(ctx, s.primaryServer.ID)
+	}, false},
+}
+
+// Rate limited requests are covered by separate tests, because the scheduler
+// pauses all requests for at least a second after a HTTP 429.
+func TestClientRetriesTransientFaults(t *testing.T) {
+	transientFaults := []fault{
+		statusFault(http.StatusInternalServerError),
+		statusFault(http.StatusBadGateway),
+		statusFault(http.StatusServiceUnavailable),
+		connectionResetFault(),
+	}
+
+	for _, method := range clientMethods {
+		for _, f := range transientFaults {
+			method, f := method, f
+			t.Run(method.name, func(t *testing.T) {
+				t.Parallel()
+				s := createFaultTestSetup(t)
+				s.injector.inject(f, f)
+
+				err := method.call(context.Background(), s)
+
+				assert.NoError(t, err)
+				assert.Len(t, s.injector.attemptTimes(), 3)
+			})
+		}
+	}
+}
+
+func TestClientReturnsTypedErrorOnceRetriesAreExhausted(t *testing.T) {
+	tests := []struct {
+		fault    fault
+		expected error
+	}{
+		{statusFault(http.StatusInternalServerError), ErrServer},
+		{statusFault(http.StatusBadGateway), ErrServer},
+		{statusFault(http.StatusServiceUnavailable), ErrServer},
+		{connectionResetFault(), syscall.ECONNRESET},
+	}
+
+	for _, method := range clientMethods {
+		for _, test := range tests {
+			method, test := method, test
+			t.Run(method.name, func(t *testing.T) {
+				t.Parallel()
+				s := createFaultTestSetup(t)
+				for i := 0; i <= testRetryConfig.maxRetries; i++ {
+					s.injector.inject(test.fault)
+				}
+
+				err := method.call(context.Background(), s)
+
+				assert.True(t, errors.Is(err, test.expected), "unexpected error %v", err)
+				assert.Len(t, s.injector.attemptTimes(), testRetryConfig.maxRetries+1)
+			})
+		}
+	}
+}
+
+func TestClientDoesNotRetryClientErrors(t *testing.T) {
+	tests := []struct {
+		status   int
+		expected error
+	}{
+		{http.StatusUnauthorized, ErrUnauthorized},
+		{http.StatusNotFound, ErrNotFound},
+		{http.StatusBadRequest, nil},
+	}
+
+	for _, method := range clientMethods {
+		for _, test := range tests {
+			method, test := method, test
+			t.Run(method.name, func(t *testing.T) {
+				t.Parallel()
+				s := createFaultTestSetup(t)
+				s.injector.inject(statusFault(test.status))
+
+				err := method.call(context.Background(), s)
+
+				assert.Len(t, s.injector.attemptTimes(), 1)
+				if test.status == http.StatusNotFound && err == nil {
+					// List methods return an empty list instead
+					return
+				}
+				if test.expected != nil {
+					assert.True(t, errors.Is(err, test.expected), "unexpected error %v", err)
+				} else {
+					var apiError *APIError
+					assert.True(t, errors.As(err, &apiError), "unexpected error %v", err)
+				}
+			})
+		}
+	}
+}
+
+func TestClientRetriesValidationErrorsOfWritesAFewTimes(t *testing.T) {
+	for _, method := range clientMethods {
+		method := method
+		t.Run(method.name, func(t *testing.T) {
+			t.Parallel()
+			s := createFaultTestSetup(t)
+			for i := 0; i <= testRetryConfig.maxRetries; i++ {
+				s.injector.inject(statusFault(http.StatusUnprocessableEntity))
+			}
+
+			err := method.call(context.Background(), s)
+
+			assert.True(t, errors.Is(err, ErrValidation), "unexpected error %v", err)
+			// Validating a zone file writes nothing
+			if s.injector.method == http.MethodGet || method.name == "ValidateZoneFile" {
+				assert.Len(t, s.injector.attemptTimes(), 1)
+			} else {
+				assert.Len(t, s.injector.attemptTimes(), 1+maxConflictRetries)
+			}
+		})
+	}
+}
+
+func TestClientRetriesWriteRejectedByConcurrentWrite(t *testing.T) {
+	s := createFaultTestSetup(t)
+	// The API rejected a concurrent write of another client to the zone
+	s.injector.inject(fault{status: http.StatusUnprocessableEntity, body: `{"error":{"message":"422 Unprocessable Entity","code":422}}`})
+
+	record, err := s.client.CreateRecord(context.Background(), CreateRecordOpts{ZoneID: s.zone.ID, Name: "mail", Type: "A", Value: "192.0.2.2"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "mail", record.Name)
+	assert.Len(t, s.server.Records(s.zone.ID), 4+1+1)
+	assert.Len(t, s.injector.attemptTimes(), 2)
+}
+
+func TestClientReturnsErrorOnTruncatedJSON(t *testing.T) {
+	for _, method := range clientMethods {
+		if !method.parsesJSON {
+			continue
+		}
+		method := method
+		t.Run(method.name, func(t *testing.T) {
+			t.Parallel()
+			s := createFaultTestSetup(t)
+			s.injector.inject(fault{truncate: true})
+
+			err := method.call(context.Background(), s)
+
+			var syntaxError *json.SyntaxError
+			assert.True(t, errors.As(err, &syntaxError), "unexpected error %v", err)
+			assert.Len(t, s.injector.attemptTimes(), 1)
+		})
+	}
+}
+
+func TestClientGivesUpOnSlowResponseWhenContextExpires(t *testing.T) {
+	for _, method := range clientMethods {
+		method := method
+		t.Run(method.name, func(t *testing.T) {
+			t.Parallel()
+			s := createFaultTestSetup(t)
+			s.injector.inject(fault{delay: 5 * time.Second})
+			ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+			defer cancel()
+
+			start := time.Now()
+			err := method.call(ctx, s)
+
+			assert.True(t, errors.Is(err, context.DeadlineExceeded), "unexpected error %v", err)
+			assert.Less(t, int64(time.Since(start)), int64(time.Second))
+			assert.Len(t, s.injector.attemptTimes(), 1)
+		})
+	}
+}
+
+func TestClientWaitsForSlowResponse(t *testing.T) {
+	s := createFaultTestSetup(t)
+	s.injector.inject(fault{delay: 100 * time.Millisecond})
+
+	zone, err := s.client.GetZone(context.Background(), s.zone.ID)
+
+	assert.NoError(t, err)
+	assert.Equal(t, s.zone.ID, zone.ID)
+	assert.Len(t, s.injector.attemptTimes(), 1)
+}
+
+func TestClientBacksOffExponentially(t *testing.T) {
+	s := createFaultTestSetup(t)
+	s.injector.inject(
+		statusFault(http.StatusInternalServerError),
+		statusFault(http.StatusInternalServerError),
+		statusFault(http.StatusInternalServerError),
+	)
+
+	_, err := s.client.GetZone(context.Background(), s.zone.ID)
+
+	assert.NoError(t, err)
+	attempts := s.injector.attemptTimes()
+	assert.Len(t, attempts, 4)
+	// The waits are 10ms, 20ms and 40ms, the last one capped by waitMax
+	for i, minWait := range []time.Duration{10 * time.Millisecond, 20 * time.Millisecond, 40 * time.Millisecond} {
+		assert.GreaterOrEqual(t, int64(attempts[i+1].Sub(attempts[i])), int64(minWait), "wait before attempt %d", i+2)
+	}
+}
+
+func TestClientHonoursRetryAfter(t *testing.T) {
+	s := createFaultTestSetup(t)
+	s.injector.inject(rateLimitFault("1"))
+
+	_, err := s.client.GetZone(context.Background(), s.zone.ID)
+
+	assert.NoError(t, err)
+	attempts := s.injector.attemptTimes()
+	assert.Len(t, attempts, 2)
+	// Retry-After takes precedence over the much shorter waitMax
+	assert.GreaterOrEqual(t, int64(attempts[1].Sub(attempts[0])), int64(time.Second))
+}
+
+func TestClientReturnsRateLimitedErrorOnceRetriesAreExhausted(t *testing.T) {
+	s := createFaultTestSetup(t)
+	s.client.httpClient = newHTTPClient(s.injector, s.client.scheduler, retryConfig{maxRetries: 1, waitMin: testRetryConfig.waitMin, waitMax: testRetryConfig.waitMax})
+	s.injector.inject(rateLimitFault("1"), rateLimitFault("1"))
+
+	_, err := s.client.GetZone(context.Background(), s.zone.ID)
+
+	var rateLimitedError *RateLimitedError
+	assert.True(t, errors.As(err, &rateLimitedError), "unexpected error %v", err)
+	assert.Equal(t, time.Second, rateLimitedError.RetryAfter)
+	assert.Len(t, s.injector.attemptTimes(), 2)
+}
+
+func TestClientRetriesAttemptsExceedingTheRequestTimeout(t *testing.T) {
+	s := createFaultTestSetup(t)
+	s.client.httpClient = newHTTPClient(&timeoutTransport{timeout: 50 * time.Millisecond, next: s.injector}, s.client.scheduler, testRetryConfig)
+	s.injector.inject(fault{delay: 5 * time.Second})
+
+	start := time.Now()
+	zone, err := s.client.GetZone(context.Background(), s.zone.ID)
+
+	assert.NoError(t, err)
+	assert.Equal(t, s.zone.ID, zone.ID)
+	assert.Less(t, int64(time.Since(start)), int64(time.Second))
+	assert.Len(t, s.injector.attemptTimes(), 2)
+}
+
+func TestClientWithoutRetriesFailsAtOnce(t *testing.T) {
+	s := createFaultTestSetup(t)
+	s.client.httpClient = newHTTPClient(s.injector, s.client.scheduler, retryConfig{maxRetries: 0, waitMin: time.Second, waitMax: time.Second})
+	s.injector.inject(statusFault(http.StatusServiceUnavailable))
+
+	_, err := s.client.GetZone(context.Background(), s.zone.ID)
+
+	assert.True(t, errors.Is(err, ErrServer), "unexpected error %v", err)
+	assert.Len(t, s.injector.attemptTimes(), 1)
+}
+
+func TestClientFailsOnValidationErrorAfterFewRetries(t *testing.T) {
+	s := createFaultTestSetup(t)
+
+	_, err := s.client.CreateRecord(context.Background(), CreateRecordOpts{ZoneID: s.zone.ID, Name: "www", Type: "AAAA", Value: "192.0.2.1"})
+
+	var validationError *ValidationError
+	assert.True(t, errors.As(err, &validationError), "unexpected error %v", err)
+	assert.Len(t, s.injector.attemptTimes(), 1+maxConflictRetries)
+}
+
+func TestClientReturnsRecordCreatedByLostAttempt(t *testing.T) {
+	s := createFaultTestSetup(t)
+	s.injector.inject(lostResponseFault())
+
+	record, err := s.client.CreateRecord(context.Background(), CreateRecordOpts{ZoneID: s.zone.ID, Name: "mail", Type: "A", Value: "192.0.2.2"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "mail", record.Name)
+	var created []fake.Record
+	for _, r := range s.server.Records(s.zone.ID) {
+		if r.Name == "mail" {
+			created = append(created, r)
+		}
+	}
+	assert.Len(t, created, 1, "the record should not be duplicated")
+	assert.Equal(t, created[0].ID, record.ID)
+	assert.Len(t, s.injector.attemptTimes(), 1)
+}
+
+func TestClientReturnsZoneCreatedByLostAttempt(t *testing.T) {
+	s := createFaultTestSetup(t)
+	s.injector.inject(lostResponseFault())
+
+	zone, err := s.client.CreateZone(context.Background(), CreateZoneOpts{Name: "another.online", TTL: 60})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "another.online", zone.Name)
+	assert.Len(t, s.server.Zones(), 2)
+	assert.Len(t, s.injector.attemptTimes(), 1)
+}
+
+func TestClientReturnsZoneCreatedByAttemptWhenRetryFails(t *testing.T) {
+	s := createFaultTestSetup(t)
+	// The lookup before the retry fails, so the retries are rejected because
+	// the zone exists already
+	lookups := &lookupFailingTransport{next: s.injector, failures: 1 + testRetryConfig.maxRetries}
+	s.client.httpClient = newHTTPClient(lookups, s.client.scheduler, testRetryConfig)
+	s.injector.inject(lostResponseFault())
+
+	zone, err := s.client.CreateZone(context.Background(), CreateZoneOpts{Name: "another.online", TTL: 60})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "another.online", zone.Name)
+	assert.Len(t, s.injector.attemptTimes(), 2+maxConflictRetries)
+	assert.Equal(t, 0, lookups.failures)
+}
+
+func TestClientDoesNotReturnExistingZoneAfterRateLimitedAttempt(t *testing.T) {
+	s := createFaultTestSetup(t)
+	s.injector.inject(rateLimitFault("1"))
+
+	zone, err := s.client.CreateZone(context.Background(), CreateZoneOpts{Name: "faults.online", TTL: 60})
+
+	assert.Nil(t, zone)
+	assert.True(t, errors.Is(err, ErrValidation), "unexpected error %v", err)
+	assert.Len(t, s.injector.attemptTimes(), 2+maxConflictRetries)
+}
+
+func TestClientDoesNotReturnExistingRecordAfterRateLimitedAttempt(t *testing.T) {
+	s := createFaultTestSetup(t)
+	// The retries are rejected, while the zone has a record matching the request
+	s.injector.inject(rateLimitFault("1"))
+	for i := 0; i <= maxConflictRetries; i++ {
+		s.injector.inject(statusFault(http.StatusUnprocessableEntity))
+	}
+
+	record, err := s.client.CreateRecord(context.Background(), CreateRecordOpts{ZoneID: s.zone.ID, Name: s.record.Name, Type: s.record.Type, Value: s.record.Value})
+
+	assert.Nil(t, record)
+	assert.True(t, errors.Is(err, ErrValidation), "unexpected error %v", err)
+	assert.Len(t, s.injector.attemptTimes(), 2+maxConflictRetries)
+}
+
+func TestClientBulkCreatesRecordsOnceAfterLostAttempt(t *testing.T) {
+	s := createFaultTestSetup(t)
+	s.injector.inject(lostResponseFault())
+
+	records, err := s.client.BulkCreateRecords(context.Background(), []CreateRecordOpts{
+		{ZoneID: s.zone.ID, Name: "mail", Type: "A", Value: "192.0.2.2"},
+		{ZoneID: s.zone.ID, Name: "ftp", Type: "A", Value: "192.0.2.3"},
+	})
+
+	assert.NoError(t, err)
+	assert.Len(t, records, 2)
+	assert.Len(t, s.server.Records(s.zone.ID), 4+1+2, "the records should not be duplicated")
+	assert.Len(t, s.injector.attemptTimes(), 1)
+}
+
+func TestClientBulkCreatesOnlyRecordsMissingAfterLostAttempt(t *testing.T) {
+	s := createFaultTestSetup(t)
+	s.injector.inject(lostResponseFault())
+
+	// The lost attempt creates the valid record only, so the invalid one is
+	// sent again and rejected
+	records, err := s.client.BulkCreateRecords(context.Background(), []CreateRecordOpts{
+		{ZoneID: s.zone.ID, Name: "mail", Type: "A", Value: "192.0.2.2"},
+		{ZoneID: s.zone.ID, Name: "ftp", Type: "A", Value: "not an address"},
+	})
+
+	var bulkError *BulkRecordsError
+	assert.True(t, errors.As(err, &bulkError), "unexpected error %v", err)
+	assert.Len(t, bulkError.FailedRecords, 1)
+	assert.Equal(t, "ftp", bulkError.FailedRecords[0].Name)
+	assert.Len(t, records, 1)
+	assert.Equal(t, "mail", records[0].Name)
+	assert.Len(t, s.server.Records(s.zone.ID), 4+1+1, "the record should not be duplicated")
+	assert.Len(t, s.injector.attemptTimes(), 2)
+}
+
+func TestClientBulkCreatesRecordsAgainIfAttemptWasNotProcessed(t *testing.T) {
+	s := createFaultTestSetup(t)
+	s.injector.inject(connectionResetFault())
+
+	records, err := s.client.BulkCreateRecords(context.Background(), []CreateRecordOpts{
+		{ZoneID: s.zone.ID, Name: "mail", Type: "A", Value: "192.0.2.2"},
+		{ZoneID: s.zone.ID, Name: "ftp", Type: "A", Value: "192.0.2.3"},
+	})
+
+	assert.NoError(t, err)
+	assert.Len(t, records, 2)
+	assert.Len(t, s.server.Records(s.zone.ID), 4+1+2)
+	assert.Len(t, s.injector.attemptTimes(), 2)
+}
+
+func TestClientCreatesRecordAgainIfAttemptWasNotProcessed(t *testing.T) {
+	s := createFaultTestSetup(t)
+	s.injector.inject(statusFault(http.StatusBadGateway))
+
+	_, err := s.client.CreateRecord(context.Background(), CreateRecordOpts{ZoneID: s.zone.ID, Name: "mail", Type: "A", Value: "192.0.2.2"})
+
+	assert.NoError(t, err)
+	assert.Len(t, s.server.Records(s.zone.ID), 4+1+1)
+	assert.Len(t, s.injector.attemptTimes(), 2)
+}
+
+func TestClientIgnoresNotFoundAfterRetriedDelete(t *testing.T) {
+	tests := []struct {
+		name   string
+		delete func(ctx context.Context, s faultTestSetup) error
+	}{
+		{"DeleteZone", func(ctx context.Context, s faultTestSetup) error {
+			return s.client.DeleteZone(ctx, s.zone.ID)
+		}},
+		{"DeleteRecord", func(ctx context.Context, s faultTestSetup) error {
+			return s.client.DeleteRecord(ctx, s.record.ID)
+		}},
+		{"DeletePrimaryServer", func(ctx context.Context, s faultTestSetup) error {
+			return s.client.DeletePrimaryServer(ctx, s.primaryServer.ID)
+		}},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			s := createFaultTestSetup(t)
+			s.injector.inject(lostResponseFault())
+
+			err := test.delete(context.Background(), s)
+
+			assert.NoError(t, err)
+			assert.Len(t, s.injector.attemptTimes(), 2)
+		})
+	}
+}
+
+func TestClientSendsDeletesOnlyWhileNoOtherWriteIsSent(t *testing.T) {
+	s := createFaultTestSetup(t)
+	unlock, err := s.client.scheduler.lockWrites(context.Background())
+	assert.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	err = s.client.DeleteRecord(ctx, s.record.ID)
+
+	assert.True(t, errors.Is(err, context.DeadlineExceeded), "unexpected error %v", err)
+	assert.Empty(t, s.injector.attemptTimes())
+	unlock()
+	assert.NoError(t, s.client.DeleteRecord(context.Background(), s.record.ID))
+}
+
+// lookupFailingTransport fails the next GET requests with a server error
+type lookupFailingTransport struct {
+	next     http.RoundTripper
+	failures int
+}
+
+func (t *lookupFailingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method != http.MethodGet || t.failures == 0 {
+		return t.next.RoundTrip(req)
+	}
+	t.failures--
+	return &http.Response{
+		Status:     http.StatusText(http.StatusInternalServerError),
+		StatusCode: http.StatusInternalServerError,
+		Header:     http.Header{"Content-Type": []string{jsonContentType}},
+		Body:       ioutil.NopCloser(bytes.NewBufferString(`{"message":"injected fault"}`)),
+		Request:    req,
+	}, nil
+}
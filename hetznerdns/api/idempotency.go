@@ -0,0 +1,106 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+)
+
+// errExistsAfterRetry stops the retries of a request which created an object
+// with an earlier attempt already.
+var errExistsAfterRetry = errors.New("object was created by an earlier attempt")
+
+type retryGuardKey struct{}
+
+// retryGuard makes retries of a request safe which isn't idempotent, like
+// creating a record. If an attempt fails without a response or with a server
+// error, the request may have been processed anyway. Before the request is
+// sent again, lookup checks whether the object exists already.
+type retryGuard struct {
+	// lookup reports whether the object the request creates exists. It is
+	// nil for requests which only need to know whether they were retried.
+	lookup func(ctx context.Context) (bool, error)
+
+	// retried is true once the request was sent more than once
+	retried bool
+	// uncertain is true if the last attempt may have been processed
+	uncertain bool
+	// anyUncertain is true once any attempt may have been processed
+	anyUncertain bool
+	// exists is true once lookup found the object
+	exists bool
+}
+
+// withRetryGuard returns a context which guards the retries of the request
+// sent with it.
+func withRetryGuard(ctx context.Context, lookup func(ctx context.Context) (bool, error)) (context.Context, *retryGuard) {
+	guard := &retryGuard{lookup: lookup}
+	return context.WithValue(ctx, retryGuardKey{}, guard), guard
+}
+
+// withoutRetryGuard returns a context for the requests sent by a lookup,
+// which must not be guarded by the guard of the request they look up.
+func withoutRetryGuard(ctx context.Context) context.Context {
+	return context.WithValue(ctx, retryGuardKey{}, (*retryGuard)(nil))
+}
+
+func retryGuardFrom(ctx context.Context) *retryGuard {
+	guard, _ := ctx.Value(retryGuardKey{}).(*retryGuard)
+	return guard
+}
+
+// attemptFailed is called with the result of every attempt which is retried
+func (g *retryGuard) attemptFailed(resp *http.Response) {
+	g.uncertain = resp == nil || resp.StatusCode >= http.StatusInternalServerError
+	if g.uncertain {
+		g.anyUncertain = true
+	}
+}
+
+// beforeRetry is called before a request is sent again. It returns
+// errExistsAfterRetry if the object was created by an earlier attempt.
+func (g *retryGuard) beforeRetry(req *http.Request) error {
+	g.retried = true
+	if g.lookup == nil || !g.uncertain {
+		return nil
+	}
+
+	ctx := withoutRetryGuard(req.Context())
+	exists, err := g.lookup(ctx)
+	if err != nil {
+		// Sending the request again is the best we can do
+		logDebug(ctx, "Lookup before retrying request failed", "http_method", req.Method, "http_url", req.URL.String(), "error", err.Error())
+		return nil
+	}
+	if exists {
+		logDebug(ctx, "Earlier attempt of request succeeded, not retrying", "http_method", req.Method, "http_url", req.URL.String())
+		g.exists = true
+		return errExistsAfterRetry
+	}
+	return nil
+}
+
+// existsAfter reports whether the object a request creates exists even
+// though the request failed with err. That's the case if an earlier attempt
+// created it, and a retry either wasn't sent or failed because the object
+// exists already. Only attempts without a response or with a server error
+// may have created it, a rejected attempt like a HTTP 429 didn't. Otherwise
+// the object existed before the request and must not be returned as created.
+func (g *retryGuard) existsAfter(ctx context.Context, err error) bool {
+	if g.exists {
+		return true
+	}
+	if !g.anyUncertain || !errors.Is(err, ErrValidation) {
+		return false
+	}
+	exists, lookupErr := g.lookup(withoutRetryGuard(ctx))
+	return lookupErr == nil && exists
+}
+
+// prepareRetry is the retryablehttp.PrepareRetry hook of the Client
+func prepareRetry(req *http.Request) error {
+	if guard := retryGuardFrom(req.Context()); guard != nil {
+		return guard.beforeRetry(req)
+	}
+	return nil
+}
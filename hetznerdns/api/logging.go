@@ -0,0 +1,214 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+const (
+	// logSubsystem is the name of the tflog subsystem of the API client. Its
+	// level is set with TF_LOG_PROVIDER_HETZNERDNS_API.
+	logSubsystem = "hetznerdns_api"
+
+	apiTokenHeader = "Auth-API-Token"
+	maskedValue    = "***"
+)
+
+// loggedHeaders are the rate limit headers logged with every response
+var loggedHeaders = map[string]string{
+	"Ratelimit-Limit":     "ratelimit_limit",
+	"Ratelimit-Remaining": "ratelimit_remaining",
+	"Ratelimit-Reset":     "ratelimit_reset",
+	"Retry-After":         "retry_after",
+}
+
+// logLevelEnvVars are the environment variables which set the level of the
+// API client subsystem, in the order of their precedence. Without its own
+// level the subsystem logs at the level of the provider and the provider at
+// the level of Terraform.
+var logLevelEnvVars = []string{
+	"TF_LOG_PROVIDER_HETZNERDNS_API",
+	"TF_LOG_PROVIDER_HETZNERDNS",
+	"TF_LOG",
+}
+
+// subsystemLevel returns the level of the API client subsystem
+func subsystemLevel() hclog.Level {
+	// Acceptance tests log at TRACE level to TF_ACC_LOG_PATH
+	if os.Getenv("TF_ACC_LOG_PATH") != "" {
+		return hclog.Trace
+	}
+	for _, envVar := range logLevelEnvVars {
+		value := os.Getenv(envVar)
+		if strings.EqualFold(value, "JSON") {
+			return hclog.Trace
+		}
+		if level := hclog.LevelFromString(value); level != hclog.NoLevel {
+			return level
+		}
+	}
+	return hclog.Off
+}
+
+type logLevelKey struct{}
+
+// withLogSubsystem returns a context with the logger of the API client
+// subsystem. It returns ctx unchanged if it carries no provider logger,
+// which is only the case outside of Terraform, e.g. in unit tests.
+func withLogSubsystem(ctx context.Context) context.Context {
+	level := subsystemLevel()
+	subsystemCtx := tflog.NewSubsystem(ctx, logSubsystem, tflog.WithLevel(level))
+	// NewSubsystem returns ctx unchanged if there is no provider logger.
+	// Logging to the subsystem would panic in that case.
+	if subsystemCtx == ctx {
+		return ctx
+	}
+	return context.WithValue(subsystemCtx, logLevelKey{}, level)
+}
+
+// logEnabled reports whether ctx carries the logger of the API client
+// subsystem and it logs messages at level.
+func logEnabled(ctx context.Context, level hclog.Level) bool {
+	subsystemLevel, ok := ctx.Value(logLevelKey{}).(hclog.Level)
+	return ok && subsystemLevel != hclog.Off && subsystemLevel <= level
+}
+
+func logDebug(ctx context.Context, msg string, args ...interface{}) {
+	if logEnabled(ctx, hclog.Debug) {
+		tflog.SubsystemDebug(ctx, logSubsystem, msg, args...)
+	}
+}
+
+func logTrace(ctx context.Context, msg string, args ...interface{}) {
+	if logEnabled(ctx, hclog.Trace) {
+		tflog.SubsystemTrace(ctx, logSubsystem, msg, args...)
+	}
+}
+
+type attemptCounterKey struct{}
+
+// withAttemptCounter returns a context which counts the attempts to send a
+// request, so retries can be told apart in the log.
+func withAttemptCounter(ctx context.Context) context.Context {
+	return context.WithValue(ctx, attemptCounterKey{}, new(int32))
+}
+
+func nextAttempt(ctx context.Context) int32 {
+	counter, ok := ctx.Value(attemptCounterKey{}).(*int32)
+	if !ok {
+		return 1
+	}
+	return atomic.AddInt32(counter, 1)
+}
+
+// loggingTransport is a http.RoundTripper which logs every attempt to send
+// a request. Bodies and headers are only read and logged at TRACE level and
+// the API token is always masked.
+type loggingTransport struct {
+	next http.RoundTripper
+}
+
+// See https://golang.org/pkg/net/http/#RoundTripper
+func (t *loggingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	apiToken := req.Header.Get(apiTokenHeader)
+	args := []interface{}{
+		"http_method", req.Method,
+		"http_url", req.URL.String(),
+		"http_attempt", nextAttempt(ctx),
+	}
+
+	logDebug(ctx, "Sending HTTP request", args...)
+	if logEnabled(ctx, hclog.Trace) {
+		body, err := peekRequestBody(req)
+		if err != nil {
+			return nil, err
+		}
+		logTrace(ctx, "HTTP request details", append(args,
+			"http_request_headers", maskHeaders(req.Header),
+			"http_request_body", maskToken(body, apiToken),
+		)...)
+	}
+
+	start := time.Now()
+	resp, err := t.next.RoundTrip(req)
+	args = append(args, "http_duration_ms", time.Since(start).Milliseconds())
+	if err != nil {
+		logDebug(ctx, "HTTP request failed", append(args, "error", err.Error())...)
+		return nil, err
+	}
+
+	args = append(args, "http_status_code", resp.StatusCode)
+	for header, key := range loggedHeaders {
+		if value := resp.Header.Get(header); value != "" {
+			args = append(args, key, value)
+		}
+	}
+	logDebug(ctx, "Received HTTP response", args...)
+	if logEnabled(ctx, hclog.Trace) {
+		body, err := peekResponseBody(resp)
+		if err != nil {
+			return nil, err
+		}
+		logTrace(ctx, "HTTP response details", append(args,
+			"http_response_headers", maskHeaders(resp.Header),
+			"http_response_body", maskToken(body, apiToken),
+		)...)
+	}
+	return resp, nil
+}
+
+// peekRequestBody reads the body of req and replaces it with a copy
+func peekRequestBody(req *http.Request) (string, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return "", nil
+	}
+	body, err := ioutil.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return "", err
+	}
+	req.Body = ioutil.NopCloser(bytes.NewReader(body))
+	return string(body), nil
+}
+
+// peekResponseBody reads the body of resp and replaces it with a copy
+func peekResponseBody(resp *http.Response) (string, error) {
+	body, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return "", err
+	}
+	resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+	return string(body), nil
+}
+
+// maskHeaders returns the headers as a map with the API token masked
+func maskHeaders(header http.Header) map[string]string {
+	masked := make(map[string]string, len(header))
+	for name, values := range header {
+		if http.CanonicalHeaderKey(name) == http.CanonicalHeaderKey(apiTokenHeader) {
+			masked[name] = maskedValue
+			continue
+		}
+		masked[name] = strings.Join(values, ", ")
+	}
+	return masked
+}
+
+// maskToken replaces all occurrences of the API token in s
+func maskToken(s string, apiToken string) string {
+	if apiToken == "" {
+		return s
+	}
+	return strings.ReplaceAll(s, apiToken, maskedValue)
+}
@@ -0,0 +1,149 @@
+package api
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/terraform-plugin-log/tfsdklog"
+	"github.com/stretchr/testify/assert"
+	"github.com/timohirt/terraform-provider-hetznerdns/hetznerdns/api/fake"
+)
+
+// setLogLevel sets the level of the API client subsystem for the test
+func setLogLevel(t *testing.T, level string) {
+	os.Setenv("TF_LOG_PROVIDER_HETZNERDNS_API", level)
+	t.Cleanup(func() { os.Unsetenv("TF_LOG_PROVIDER_HETZNERDNS_API") })
+}
+
+// createLoggingContext returns a context with a provider logger, like the one
+// Terraform passes to the provider, and a function returning its output. The
+// API client subsystem logs at TRACE level.
+func createLoggingContext(t *testing.T) (context.Context, func() string) {
+	setLogLevel(t, "TRACE")
+
+	logFile, err := ioutil.TempFile(t.TempDir(), "log")
+	assert.NoError(t, err)
+
+	// The logger writes to os.Stderr as it is at the time of its creation
+	stderr := os.Stderr
+	os.Stderr = logFile
+	ctx := tfsdklog.NewRootProviderLogger(context.Background())
+	os.Stderr = stderr
+
+	return ctx, func() string {
+		output, err := ioutil.ReadFile(logFile.Name())
+		assert.NoError(t, err)
+		return string(output)
+	}
+}
+
+func TestClientLogsRequestsWithMaskedAPIToken(t *testing.T) {
+	server := fake.NewServer("secret-api-token")
+	defer server.Close()
+	zone := server.AddZone("mydomain.com", 3600)
+	client, _ := NewClient("secret-api-token", ClientOpts{Endpoint: server.URL()})
+	ctx, logOutput := createLoggingContext(t)
+
+	_, err := client.ValidateZoneFile(ctx, "www IN TXT secret-api-token\n")
+	assert.NoError(t, err)
+	_, err = client.GetZone(ctx, zone.ID)
+	assert.NoError(t, err)
+
+	output := logOutput()
+	assert.NotContains(t, output, "secret-api-token")
+	assert.Contains(t, output, `"@module":"provider.hetznerdns_api"`)
+	assert.Contains(t, output, `"@message":"Received HTTP response"`)
+	assert.Contains(t, output, `"http_method":"GET"`)
+	assert.Contains(t, output, `"http_url":"`+server.URL()+`/zones/`+zone.ID+`"`)
+	assert.Contains(t, output, `"http_status_code":200`)
+	assert.Contains(t, output, `"http_attempt":1`)
+	assert.Contains(t, output, `"http_duration_ms"`)
+	assert.Contains(t, output, `"Auth-Api-Token":"***"`)
+	assert.Contains(t, output, `"http_request_body":"www IN TXT ***\n"`)
+	assert.Contains(t, output, `"http_response_body":"{\"zone\":{\"id\":\"`+zone.ID)
+}
+
+func TestClientLogsRetryAttempts(t *testing.T) {
+	s := createFaultTestSetup(t)
+	s.injector.inject(statusFault(503))
+	ctx, logOutput := createLoggingContext(t)
+
+	_, err := s.client.GetZone(ctx, s.zone.ID)
+	assert.NoError(t, err)
+
+	output := logOutput()
+	assert.Contains(t, output, `"http_attempt":1`)
+	assert.Contains(t, output, `"http_status_code":503`)
+	assert.Contains(t, output, `"http_attempt":2`)
+}
+
+func TestClientDoesNotReadBodiesBelowTraceLevel(t *testing.T) {
+	server := fake.NewServer("token")
+	defer server.Close()
+	client, _ := NewClient("token", ClientOpts{Endpoint: server.URL()})
+	ctx, logOutput := createLoggingContext(t)
+	setLogLevel(t, "DEBUG")
+
+	_, err := client.ValidateZoneFile(ctx, "www IN A 192.0.2.1\n")
+	assert.NoError(t, err)
+
+	output := logOutput()
+	assert.Contains(t, output, `"@message":"Received HTTP response"`)
+	assert.NotContains(t, output, `"@message":"HTTP response details"`)
+	assert.NotContains(t, output, "http_request_body")
+	assert.NotContains(t, output, "http_response_body")
+}
+
+func TestClientLogsNothingWithoutLogLevel(t *testing.T) {
+	server := fake.NewServer("token")
+	defer server.Close()
+	client, _ := NewClient("token", ClientOpts{Endpoint: server.URL()})
+	ctx, logOutput := createLoggingContext(t)
+	for _, envVar := range logLevelEnvVars {
+		if value, ok := os.LookupEnv(envVar); ok {
+			os.Unsetenv(envVar)
+			defer os.Setenv(envVar, value)
+		}
+	}
+
+	_, err := client.ListZones(ctx, ListZonesOpts{})
+	assert.NoError(t, err)
+
+	assert.Empty(t, logOutput())
+}
+
+func TestLogEnabled(t *testing.T) {
+	assert.False(t, logEnabled(context.Background(), hclog.Error))
+
+	ctx := context.WithValue(context.Background(), logLevelKey{}, hclog.Debug)
+	assert.True(t, logEnabled(ctx, hclog.Debug))
+	assert.True(t, logEnabled(ctx, hclog.Info))
+	assert.False(t, logEnabled(ctx, hclog.Trace))
+
+	ctx = context.WithValue(context.Background(), logLevelKey{}, hclog.Off)
+	assert.False(t, logEnabled(ctx, hclog.Error))
+}
+
+func TestClientLogsNothingWithoutProviderLogger(t *testing.T) {
+	server := fake.NewServer("token")
+	defer server.Close()
+	client, _ := NewClient("token", ClientOpts{Endpoint: server.URL()})
+
+	// Must not panic without the logger Terraform passes to the provider
+	_, err := client.ListZones(context.Background(), ListZonesOpts{})
+	assert.NoError(t, err)
+}
+
+func TestMaskHeaders(t *testing.T) {
+	masked := maskHeaders(map[string][]string{
+		"Auth-Api-Token": {"secret"},
+		"Accept":         {"application/json"},
+	})
+
+	assert.Equal(t, map[string]string{"Auth-Api-Token": maskedValue, "Accept": "application/json"}, masked)
+	assert.False(t, strings.Contains(maskToken("token=secret", "secret"), "secret"))
+}
@@ -0,0 +1,32 @@
+package api
+
+// DefaultPerPage is the number of entries requested per page when
+// listing zones or records. It is the maximum the API accepts.
+const DefaultPerPage = 100
+
+// Meta represents the meta data of a response containing a list of entries
+type Meta struct {
+	Pagination Pagination `json:"pagination"`
+}
+
+// Pagination represents the pagination information of a list response
+type Pagination struct {
+	Page         int `json:"page"`
+	PerPage      int `json:"per_page"`
+	PreviousPage int `json:"previous_page"`
+	NextPage     int `json:"next_page"`
+	LastPage     int `json:"last_page"`
+	TotalEntries int `json:"total_entries"`
+}
+
+// hasNextPage returns true if there are pages left after the current one
+func (m *Meta) hasNextPage() bool {
+	return m != nil && m.Pagination.Page < m.Pagination.LastPage
+}
+
+func perPageOrDefault(perPage int) int {
+	if perPage <= 0 {
+		return DefaultPerPage
+	}
+	return perPage
+}
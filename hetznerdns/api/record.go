@@ -27,9 +27,33 @@ type CreateRecordRequest struct {
 // RecordsResponse represents a response from tha API containing a list of records
 type RecordsResponse struct {
 	Records []Record `json:"records"`
+	Meta    *Meta    `json:"meta,omitempty"`
 }
 
 // RecordResponse represents a response from the API containing only one record
 type RecordResponse struct {
 	Record Record `json:"record"`
 }
+
+// BulkCreateRecordsRequest represents the body of a POST records/bulk request
+type BulkCreateRecordsRequest struct {
+	Records []CreateRecordRequest `json:"records"`
+}
+
+// BulkCreateRecordsResponse represents the content of a POST records/bulk response
+type BulkCreateRecordsResponse struct {
+	Records        []Record `json:"records"`
+	ValidRecords   []Record `json:"valid_records"`
+	InvalidRecords []Record `json:"invalid_records"`
+}
+
+// BulkUpdateRecordsRequest represents the body of a PUT records/bulk request
+type BulkUpdateRecordsRequest struct {
+	Records []Record `json:"records"`
+}
+
+// BulkUpdateRecordsResponse represents the content of a PUT records/bulk response
+type BulkUpdateRecordsResponse struct {
+	Records       []Record `json:"records"`
+	FailedRecords []Record `json:"failed_records"`
+}
@@ -0,0 +1,235 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	// defaultRequestsPerSecond is the rate at which the token bucket of
+	// the requestScheduler is refilled until the API reports its rate limit
+	defaultRequestsPerSecond = 10
+	// defaultBurst is the number of requests which may be sent at once
+	// before the token bucket starts to throttle
+	defaultBurst = 20
+)
+
+// requestScheduler decides when a request may be sent to the API. Until the
+// API reports its rate limit in the Ratelimit-* headers, it throttles
+// requests with a client-side token bucket. From then on it keeps a budget
+// of the requests left in the current window instead, so parallel requests
+// don't exceed the limit, and pauses requests until the window resets once
+// the budget is spent. It also honours Retry-After and serializes write
+// requests.
+type requestScheduler struct {
+	mu sync.Mutex
+	// tokens, lastRefill and rate make up the token bucket used while the
+	// rate limit of the API is unknown
+	tokens     float64
+	lastRefill time.Time
+	rate       float64
+	burst      float64
+	// limit is the number of requests the API allows per window, as
+	// reported by the Ratelimit-Limit header. It is 0 while unknown.
+	limit int64
+	// remaining is the number of requests left in the current window
+	remaining int64
+	// resetAt is the time the current window ends. It is zero while
+	// unknown.
+	resetAt      time.Time
+	blockedUntil time.Time
+
+	// writeLock is held while a write request is sent
+	writeLock chan struct{}
+
+	// now is replaced in tests
+	now func() time.Time
+}
+
+func newRequestScheduler() *requestScheduler {
+	return &requestScheduler{
+		tokens:     defaultBurst,
+		lastRefill: time.Now(),
+		rate:       defaultRequestsPerSecond,
+		burst:      defaultBurst,
+		writeLock:  make(chan struct{}, 1),
+		now:        time.Now,
+	}
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// wait blocks until a request may be sent or ctx is done.
+func (s *requestScheduler) wait(ctx context.Context) error {
+	for {
+		delay := s.reserve()
+		if delay <= 0 {
+			return nil
+		}
+		if err := sleepContext(ctx, delay); err != nil {
+			return err
+		}
+	}
+}
+
+// reserve takes a request from the token bucket or from the budget of the
+// current window and returns 0, or returns how long to wait before trying
+// again.
+func (s *requestScheduler) reserve() time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	if now.Before(s.blockedUntil) {
+		return s.blockedUntil.Sub(now)
+	}
+	if s.limit == 0 {
+		return s.reserveToken(now)
+	}
+
+	if !s.resetAt.IsZero() && !now.Before(s.resetAt) {
+		// A new window started
+		s.remaining = s.limit
+		s.resetAt = time.Time{}
+	}
+	if s.remaining > 0 {
+		s.remaining--
+		return 0
+	}
+	if s.resetAt.IsZero() {
+		// Without knowing when the window ends, leave it to the API to
+		// reject requests with 429
+		return 0
+	}
+	return s.resetAt.Sub(now)
+}
+
+// reserveToken takes a token from the token bucket and returns 0, or
+// returns how long to wait until a token is available.
+func (s *requestScheduler) reserveToken(now time.Time) time.Duration {
+	s.tokens += now.Sub(s.lastRefill).Seconds() * s.rate
+	if s.tokens > s.burst {
+		s.tokens = s.burst
+	}
+	s.lastRefill = now
+
+	if s.tokens >= 1 {
+		s.tokens--
+		return 0
+	}
+	return time.Duration((1 - s.tokens) / s.rate * float64(time.Second))
+}
+
+// update adjusts the scheduler to the rate limit reported by the API in
+// the Ratelimit-* and Retry-After headers of a response.
+func (s *requestScheduler) update(ctx context.Context, resp *http.Response) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	limit, hasLimit := parseIntHeader(resp.Header, "Ratelimit-Limit")
+	remaining, hasRemaining := parseIntHeader(resp.Header, "Ratelimit-Remaining")
+	reset, hasReset := parseIntHeader(resp.Header, "Ratelimit-Reset")
+
+	if hasLimit && limit > 0 {
+		if s.limit == 0 {
+			logDebug(ctx, "Learned API rate limit", "rate_limit", limit)
+			s.remaining = limit
+		}
+		s.limit = limit
+	}
+	if hasReset {
+		s.resetAt = rateLimitResetTime(now, reset)
+	}
+	if hasRemaining && remaining < s.remaining {
+		// Never send more requests than the API allows until its window
+		// resets. Requests in flight are already taken from the budget, so
+		// a higher value reported by the API is ignored.
+		s.remaining = remaining
+	}
+	if hasRemaining && remaining == 0 && hasReset {
+		s.blockUntil(ctx, s.resetAt)
+	}
+
+	if resp.StatusCode == http.StatusTooManyRequests {
+		retryAfter := parseRetryAfter(resp.Header.Get("Retry-After"))
+		if retryAfter <= 0 {
+			retryAfter = time.Second
+		}
+		s.blockUntil(ctx, now.Add(retryAfter))
+	}
+}
+
+func (s *requestScheduler) blockUntil(ctx context.Context, until time.Time) {
+	if until.After(s.blockedUntil) {
+		logDebug(ctx, "API rate limit exhausted, pausing requests", "paused_until", until.Format(time.RFC3339))
+		s.blockedUntil = until
+	}
+}
+
+// rateLimitResetTime converts the value of a Ratelimit-Reset header, which is
+// either a UNIX timestamp or a number of seconds, to a point in time.
+func rateLimitResetTime(now time.Time, reset int64) time.Time {
+	if reset > 1000000000 {
+		return time.Unix(reset, 0)
+	}
+	return now.Add(time.Duration(reset) * time.Second)
+}
+
+func parseIntHeader(header http.Header, name string) (int64, bool) {
+	value := header.Get(name)
+	if value == "" {
+		return 0, false
+	}
+	i, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || i < 0 {
+		return 0, false
+	}
+	return i, true
+}
+
+// lockWrites serializes write requests. The API rejects concurrent writes
+// with HTTP 422, see issue #5 for context. The returned function releases
+// the lock.
+func (s *requestScheduler) lockWrites(ctx context.Context) (func(), error) {
+	select {
+	case s.writeLock <- struct{}{}:
+		return func() { <-s.writeLock }, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// scheduledTransport is a http.RoundTripper which sends every request,
+// including retries, only when the requestScheduler allows it.
+type scheduledTransport struct {
+	scheduler *requestScheduler
+	next      http.RoundTripper
+}
+
+// See https://golang.org/pkg/net/http/#RoundTripper
+func (t *scheduledTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := t.scheduler.wait(req.Context()); err != nil {
+		return nil, err
+	}
+
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	t.scheduler.update(req.Context(), resp)
+	return resp, nil
+}
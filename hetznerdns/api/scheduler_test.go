@@ -0,0 +1,165 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func createTestScheduler(now *time.Time) *requestScheduler {
+	scheduler := newRequestScheduler()
+	scheduler.lastRefill = *now
+	scheduler.now = func() time.Time { return *now }
+	return scheduler
+}
+
+func TestSchedulerAllowsBurstThenThrottlesWithoutRateLimit(t *testing.T) {
+	now := time.Unix(1600000000, 0)
+	scheduler := createTestScheduler(&now)
+
+	for i := 0; i < defaultBurst; i++ {
+		assert.Equal(t, time.Duration(0), scheduler.reserve(), "request %d should not be throttled", i)
+	}
+	assert.Equal(t, time.Second/defaultRequestsPerSecond, scheduler.reserve())
+
+	now = now.Add(time.Second / defaultRequestsPerSecond)
+	assert.Equal(t, time.Duration(0), scheduler.reserve())
+}
+
+func TestSchedulerReplacesDefaultLimitWithRateLimitOfTheAPI(t *testing.T) {
+	now := time.Unix(1600000000, 0)
+	scheduler := createTestScheduler(&now)
+
+	scheduler.update(context.Background(), &http.Response{StatusCode: http.StatusOK, Header: http.Header{
+		"Ratelimit-Limit":     []string{"300"},
+		"Ratelimit-Remaining": []string{"299"},
+		"Ratelimit-Reset":     []string{"60"},
+	}})
+
+	for i := 0; i < 299; i++ {
+		assert.Equal(t, time.Duration(0), scheduler.reserve(), "request %d should not be throttled", i)
+	}
+	assert.Equal(t, 60*time.Second, scheduler.reserve())
+}
+
+func TestSchedulerThrottlesToTheRateLimitOfTheAPI(t *testing.T) {
+	now := time.Unix(1600000000, 0)
+	scheduler := createTestScheduler(&now)
+
+	scheduler.update(context.Background(), &http.Response{StatusCode: http.StatusOK, Header: http.Header{
+		"Ratelimit-Limit":     []string{"3"},
+		"Ratelimit-Remaining": []string{"2"},
+		"Ratelimit-Reset":     []string{"60"},
+	}})
+
+	assert.Equal(t, time.Duration(0), scheduler.reserve())
+	assert.Equal(t, time.Duration(0), scheduler.reserve())
+	assert.Equal(t, 60*time.Second, scheduler.reserve())
+
+	// The whole limit is available in the next window
+	now = now.Add(60 * time.Second)
+	for i := 0; i < 3; i++ {
+		assert.Equal(t, time.Duration(0), scheduler.reserve(), "request %d should not be throttled", i)
+	}
+	assert.Equal(t, time.Duration(0), scheduler.reserve(), "the end of the next window is unknown")
+}
+
+func TestSchedulerIgnoresRemainingRequestsAboveItsBudget(t *testing.T) {
+	now := time.Unix(1600000000, 0)
+	scheduler := createTestScheduler(&now)
+	header := http.Header{
+		"Ratelimit-Limit":     []string{"2"},
+		"Ratelimit-Remaining": []string{"2"},
+		"Ratelimit-Reset":     []string{"60"},
+	}
+
+	scheduler.update(context.Background(), &http.Response{StatusCode: http.StatusOK, Header: header})
+	assert.Equal(t, time.Duration(0), scheduler.reserve())
+	assert.Equal(t, time.Duration(0), scheduler.reserve())
+
+	// A response to a request sent before the budget was spent
+	scheduler.update(context.Background(), &http.Response{StatusCode: http.StatusOK, Header: header})
+	assert.Equal(t, 60*time.Second, scheduler.reserve())
+}
+
+func TestSchedulerLimitsTokensToRemainingRequests(t *testing.T) {
+	now := time.Unix(1600000000, 0)
+	scheduler := createTestScheduler(&now)
+
+	scheduler.update(context.Background(), &http.Response{StatusCode: http.StatusOK, Header: http.Header{
+		"Ratelimit-Limit":     []string{"300"},
+		"Ratelimit-Remaining": []string{"1"},
+		"Ratelimit-Reset":     []string{"60"},
+	}})
+
+	assert.Equal(t, time.Duration(0), scheduler.reserve())
+	assert.True(t, scheduler.reserve() > 0)
+}
+
+func TestSchedulerPausesUntilRateLimitReset(t *testing.T) {
+	now := time.Unix(1600000000, 0)
+	scheduler := createTestScheduler(&now)
+
+	scheduler.update(context.Background(), &http.Response{StatusCode: http.StatusOK, Header: http.Header{
+		"Ratelimit-Remaining": []string{"0"},
+		"Ratelimit-Reset":     []string{"30"},
+	}})
+
+	assert.Equal(t, 30*time.Second, scheduler.reserve())
+}
+
+func TestSchedulerPausesUntilRateLimitResetTimestamp(t *testing.T) {
+	now := time.Unix(1600000000, 0)
+	scheduler := createTestScheduler(&now)
+
+	scheduler.update(context.Background(), &http.Response{StatusCode: http.StatusOK, Header: http.Header{
+		"Ratelimit-Remaining": []string{"0"},
+		"Ratelimit-Reset":     []string{"1600000045"},
+	}})
+
+	assert.Equal(t, 45*time.Second, scheduler.reserve())
+}
+
+func TestSchedulerHonoursRetryAfter(t *testing.T) {
+	now := time.Unix(1600000000, 0)
+	scheduler := createTestScheduler(&now)
+
+	scheduler.update(context.Background(), &http.Response{StatusCode: http.StatusTooManyRequests, Header: http.Header{
+		"Retry-After": []string{"5"},
+	}})
+
+	assert.Equal(t, 5*time.Second, scheduler.reserve())
+	now = now.Add(5 * time.Second)
+	assert.Equal(t, time.Duration(0), scheduler.reserve())
+}
+
+func TestSchedulerWaitIsCanceledWithContext(t *testing.T) {
+	now := time.Unix(1600000000, 0)
+	scheduler := createTestScheduler(&now)
+	scheduler.blockedUntil = now.Add(time.Hour)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	assert.Equal(t, context.Canceled, scheduler.wait(ctx))
+}
+
+func TestSchedulerSerializesWrites(t *testing.T) {
+	scheduler := newRequestScheduler()
+
+	unlock, err := scheduler.lockWrites(context.Background())
+	assert.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err = scheduler.lockWrites(ctx)
+	assert.Equal(t, context.DeadlineExceeded, err)
+
+	unlock()
+	unlock, err = scheduler.lockWrites(context.Background())
+	assert.NoError(t, err)
+	unlock()
+}
@@ -0,0 +1,100 @@
+package api
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// newDefaultTransport creates the transport shared by all requests of a
+// Client. All requests go to the same host, so it keeps enough idle
+// connections to that host to avoid a TLS handshake per request.
+func newDefaultTransport() *http.Transport {
+	return &http.Transport{
+		Proxy: http.ProxyFromEnvironment,
+		DialContext: (&net.Dialer{
+			Timeout:   30 * time.Second,
+			KeepAlive: 30 * time.Second,
+		}).DialContext,
+		ForceAttemptHTTP2:     true,
+		MaxIdleConns:          100,
+		MaxIdleConnsPerHost:   20,
+		IdleConnTimeout:       90 * time.Second,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ExpectContinueTimeout: 1 * time.Second,
+	}
+}
+
+// configureConnection applies the proxy and TLS options to transport. Without
+// a proxy URL, the proxy is taken from the environment.
+func configureConnection(transport *http.Transport, opts ClientOpts) error {
+	if opts.ProxyURL != "" {
+		proxyURL, err := url.Parse(opts.ProxyURL)
+		// The proxy URL may contain credentials, so it's not part of the error
+		if err != nil || proxyURL.Scheme == "" || proxyURL.Host == "" {
+			return errors.New("Proxy URL is not a valid absolute URL")
+		}
+		transport.Proxy = http.ProxyURL(proxyURL)
+	}
+
+	if opts.CACertPEM == "" && !opts.InsecureSkipVerify {
+		return nil
+	}
+	tlsConfig := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: opts.InsecureSkipVerify,
+	}
+	if opts.CACertPEM != "" {
+		// The CA is trusted in addition to the system CAs, so the API can be
+		// reached with and without a TLS intercepting proxy.
+		rootCAs, err := x509.SystemCertPool()
+		if err != nil {
+			rootCAs = x509.NewCertPool()
+		}
+		if !rootCAs.AppendCertsFromPEM([]byte(opts.CACertPEM)) {
+			return errors.New("CA certificate contains no PEM encoded certificate")
+		}
+		tlsConfig.RootCAs = rootCAs
+	}
+	transport.TLSClientConfig = tlsConfig
+	return nil
+}
+
+// timeoutTransport limits the time of every attempt to send a request,
+// including reading the response body. Unlike http.Client.Timeout, it
+// doesn't include the time a request waits for the requestScheduler.
+type timeoutTransport struct {
+	timeout time.Duration
+	next    http.RoundTripper
+}
+
+// See https://golang.org/pkg/net/http/#RoundTripper
+func (t *timeoutTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx, cancel := context.WithTimeout(req.Context(), t.timeout)
+	resp, err := t.next.RoundTrip(req.WithContext(ctx))
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	resp.Body = &cancelOnCloseBody{ReadCloser: resp.Body, cancel: cancel}
+	return resp, nil
+}
+
+// cancelOnCloseBody cancels the context of a request once its response body
+// is closed.
+type cancelOnCloseBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelOnCloseBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
@@ -2,6 +2,35 @@ package api
 
 // Zone represents a DNS Zone
 type Zone struct {
+	ID              string           `json:"id"`
+	Name            string           `json:"name"`
+	TTL             int              `json:"ttl"`
+	NS              []string         `json:"ns,omitempty"`
+	LegacyNS        []string         `json:"legacy_ns,omitempty"`
+	LegacyDNSHost   string           `json:"legacy_dns_host,omitempty"`
+	Registrar       string           `json:"registrar,omitempty"`
+	Owner           string           `json:"owner,omitempty"`
+	Project         string           `json:"project,omitempty"`
+	Permission      string           `json:"permission,omitempty"`
+	Status          string           `json:"status,omitempty"`
+	Verified        string           `json:"verified,omitempty"`
+	Created         string           `json:"created,omitempty"`
+	Modified        string           `json:"modified,omitempty"`
+	Paused          bool             `json:"paused,omitempty"`
+	IsSecondaryDNS  bool             `json:"is_secondary_dns,omitempty"`
+	RecordsCount    int              `json:"records_count,omitempty"`
+	TxtVerification *TxtVerification `json:"txt_verification,omitempty"`
+}
+
+// TxtVerification is the TXT record which proves the ownership of a Zone
+type TxtVerification struct {
+	Name  string `json:"name"`
+	Token string `json:"token"`
+}
+
+// UpdateZoneRequest represents the body of a PUT Zone request. Only the
+// attributes which can be changed are sent.
+type UpdateZoneRequest struct {
 	ID   string `json:"id"`
 	Name string `json:"name"`
 	TTL  int    `json:"ttl"`
@@ -28,7 +57,21 @@ type ZoneResponse struct {
 	Zone Zone `json:"zone"`
 }
 
-// GetZonesByNameResponse represents the content of a GET Zones response
-type GetZonesByNameResponse struct {
+// ZonesResponse represents the content of a GET Zones response including
+// pagination meta data
+type ZonesResponse struct {
 	Zones []Zone `json:"zones"`
+	Meta  *Meta  `json:"meta,omitempty"`
+}
+
+// ZoneFileValidation represents the content of a POST zones/file/validate response
+type ZoneFileValidation struct {
+	ParsedRecords  int      `json:"parsed_records"`
+	ValidRecords   []Record `json:"valid_records"`
+	InvalidRecords []Record `json:"invalid_records"`
+}
+
+// IsValid returns true if the zone file contains no invalid records
+func (v *ZoneFileValidation) IsValid() bool {
+	return len(v.InvalidRecords) == 0
 }
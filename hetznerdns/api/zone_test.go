@@ -30,8 +30,8 @@ func TestGetZoneResponseJson(t *testing.T) {
 	assertSerializeAndAssertEqual(t, resp, expectedJSON)
 }
 
-func TestGetZoneByNameResponseJson(t *testing.T) {
-	resp := GetZonesByNameResponse{[]Zone{{ID: "aId", Name: "aName", TTL: 60}}}
+func TestZonesResponseJson(t *testing.T) {
+	resp := ZonesResponse{Zones: []Zone{{ID: "aId", Name: "aName", TTL: 60}}}
 	expectedJSON := `{"zones":[{"id":"aId","name":"aName","ttl":60}]}`
 
 	assertSerializeAndAssertEqual(t, resp, expectedJSON)
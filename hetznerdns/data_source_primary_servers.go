@@ -0,0 +1,76 @@
+package hetznerdns
+
+import (
+	"context"
+	"errors"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/timohirt/terraform-provider-hetznerdns/hetznerdns/api"
+)
+
+func dataSourcePrimaryServers() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourcePrimaryServersRead,
+		Schema: map[string]*schema.Schema{
+			"zone_id": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"primary_servers": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"address": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"port": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourcePrimaryServersRead(c context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	tflog.Debug(c, "Reading primary servers")
+	client := m.(*api.Client)
+
+	zoneID := d.Get("zone_id").(string)
+	primaryServers, err := client.ListPrimaryServers(c, zoneID)
+	if errors.Is(err, api.ErrNotFound) {
+		return diag.Errorf("DNS zone '%s' doesn't exist", zoneID)
+	} else if err != nil {
+		return diag.Errorf("Error getting primary servers of zone %s: %s", zoneID, err)
+	}
+
+	result := make([]map[string]interface{}, len(primaryServers))
+	for i, primaryServer := range primaryServers {
+		port := 0
+		if primaryServer.Port != nil {
+			port = *primaryServer.Port
+		}
+		result[i] = map[string]interface{}{
+			"id":      primaryServer.ID,
+			"address": primaryServer.Address,
+			"port":    port,
+		}
+	}
+
+	if err := d.Set("primary_servers", result); err != nil {
+		return diag.FromErr(err)
+	}
+	d.SetId(zoneID)
+
+	return nil
+}
@@ -0,0 +1,59 @@
+package hetznerdns
+
+import (
+	"fmt"
+	"strconv"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccPrimaryServersDataSource(t *testing.T) {
+	// aZoneName must be a valid DNS domain name with an existing TLD
+	aZoneName := fmt.Sprintf("%s.online", acctest.RandString(10))
+	aZoneTTL := 60
+
+	psAddress := validPublicIpAddress
+	psPort := 53
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccAPITokenPresent(t) },
+		ProviderFactories: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccPrimaryServersDataSourceConfig(aZoneName, aZoneTTL, psAddress, psPort),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(
+						"data.hetznerdns_primary_servers.all", "primary_servers.#", "1"),
+					resource.TestCheckResourceAttrPair(
+						"data.hetznerdns_primary_servers.all", "primary_servers.0.id",
+						"hetznerdns_primary_server.ps1", "id"),
+					resource.TestCheckResourceAttr(
+						"data.hetznerdns_primary_servers.all", "primary_servers.0.address", psAddress),
+					resource.TestCheckResourceAttr(
+						"data.hetznerdns_primary_servers.all", "primary_servers.0.port", strconv.Itoa(psPort)),
+				),
+			},
+		},
+	})
+}
+
+func testAccPrimaryServersDataSourceConfig(aZoneName string, aZoneTTL int, psAddress string, psPort int) string {
+	return fmt.Sprintf(`
+resource "hetznerdns_zone" "zone1" {
+	name = "%s"
+	ttl = %d
+}
+
+resource "hetznerdns_primary_server" "ps1" {
+	zone_id = "${hetznerdns_zone.zone1.id}"
+	address = "%s"
+	port    = %d
+}
+
+data "hetznerdns_primary_servers" "all" {
+	zone_id = "${hetznerdns_primary_server.ps1.zone_id}"
+}
+`, aZoneName, aZoneTTL, psAddress, psPort)
+}
@@ -0,0 +1,91 @@
+package hetznerdns
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/timohirt/terraform-provider-hetznerdns/hetznerdns/api"
+)
+
+func dataSourceRecord() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceRecordRead,
+		Schema: map[string]*schema.Schema{
+			"zone_id": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ExactlyOneOf: []string{"zone_id", "zone_name"},
+			},
+			"zone_name": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"name": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"type": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"value": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+			},
+			"ttl": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func dataSourceRecordRead(c context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	tflog.Debug(c, "Reading record")
+	client := m.(*api.Client)
+
+	zoneID, diags := dataSourceZoneID(c, d, client)
+	if diags.HasError() {
+		return diags
+	}
+
+	opts := api.LookupRecordOpts{
+		ZoneID: zoneID,
+		Name:   d.Get("name").(string),
+		Type:   d.Get("type").(string),
+		Value:  d.Get("value").(string),
+	}
+	record, err := client.LookupRecord(c, opts)
+	if errors.Is(err, api.ErrNotFound) {
+		return diag.Diagnostics{{
+			Severity: diag.Error,
+			Summary:  "Record not found",
+			Detail:   fmt.Sprintf("There is no record '%s' in zone %s.", opts, zoneID),
+		}}
+	} else if errors.Is(err, api.ErrMultipleMatches) {
+		return diag.Diagnostics{{
+			Severity: diag.Error,
+			Summary:  "Multiple records found",
+			Detail:   fmt.Sprintf("%s. Set value to select one of them or use the hetznerdns_records data source.", err),
+		}}
+	} else if err != nil {
+		return diag.Errorf("Error getting record: %s", err)
+	}
+
+	ttl := 0
+	if record.HasTTL() {
+		ttl = *record.TTL
+	}
+	d.Set("zone_id", record.ZoneID)
+	d.Set("value", record.Value)
+	d.Set("ttl", ttl)
+	d.SetId(record.ID)
+
+	return nil
+}
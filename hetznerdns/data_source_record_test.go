@@ -0,0 +1,107 @@
+package hetznerdns
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccRecordDataSource(t *testing.T) {
+	// aZoneName must be a valid DNS domain name with an existing TLD
+	aZoneName := fmt.Sprintf("%s.online", acctest.RandString(10))
+	aZoneTTL := 60
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccAPITokenPresent(t) },
+		ProviderFactories: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccRecordDataSourceConfig(aZoneName, aZoneTTL, `
+data "hetznerdns_record" "www_a" {
+	zone_id = hetznerdns_zone.zone1.id
+	name = "www"
+	type = "A"
+
+	depends_on = [hetznerdns_record.www_a, hetznerdns_record.www_txt]
+}
+
+data "hetznerdns_record" "www_txt" {
+	zone_name = hetznerdns_zone.zone1.name
+	name = "www"
+	type = "TXT"
+
+	depends_on = [hetznerdns_record.www_a, hetznerdns_record.www_txt]
+}
+`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair(
+						"data.hetznerdns_record.www_a", "id",
+						"hetznerdns_record.www_a", "id"),
+					resource.TestCheckResourceAttr(
+						"data.hetznerdns_record.www_a", "value", "192.0.2.1"),
+					resource.TestCheckResourceAttr(
+						"data.hetznerdns_record.www_a", "ttl", "120"),
+					resource.TestCheckResourceAttrPair(
+						"data.hetznerdns_record.www_txt", "id",
+						"hetznerdns_record.www_txt", "id"),
+					resource.TestCheckResourceAttrPair(
+						"data.hetznerdns_record.www_txt", "zone_id",
+						"hetznerdns_zone.zone1", "id"),
+					resource.TestCheckResourceAttr(
+						"data.hetznerdns_record.www_txt", "ttl", "0"),
+				),
+			},
+			{
+				Config: testAccRecordDataSourceConfig(aZoneName, aZoneTTL, `
+data "hetznerdns_record" "missing" {
+	zone_id = hetznerdns_zone.zone1.id
+	name = "www"
+	type = "AAAA"
+
+	depends_on = [hetznerdns_record.www_a, hetznerdns_record.www_txt]
+}
+`),
+				ExpectError: regexp.MustCompile("Record not found"),
+			},
+			{
+				Config: testAccRecordDataSourceConfig(aZoneName, aZoneTTL, `
+data "hetznerdns_record" "ns" {
+	zone_id = hetznerdns_zone.zone1.id
+	name = "@"
+	type = "NS"
+
+	depends_on = [hetznerdns_record.www_a, hetznerdns_record.www_txt]
+}
+`),
+				ExpectError: regexp.MustCompile("Multiple records found"),
+			},
+		},
+	})
+}
+
+func testAccRecordDataSourceConfig(aZoneName string, aZoneTTL int, dataSources string) string {
+	return fmt.Sprintf(`
+resource "hetznerdns_zone" "zone1" {
+	name = "%s"
+	ttl = %d
+}
+
+resource "hetznerdns_record" "www_a" {
+	zone_id = hetznerdns_zone.zone1.id
+	name = "www"
+	type = "A"
+	value = "192.0.2.1"
+	ttl = 120
+}
+
+resource "hetznerdns_record" "www_txt" {
+	zone_id = hetznerdns_zone.zone1.id
+	name = "www"
+	type = "TXT"
+	value = "hello"
+}
+%s`, aZoneName, aZoneTTL, dataSources)
+}
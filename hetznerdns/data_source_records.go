@@ -0,0 +1,172 @@
+package hetznerdns
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/timohirt/terraform-provider-hetznerdns/hetznerdns/api"
+)
+
+func dataSourceRecords() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceRecordsRead,
+		Schema: map[string]*schema.Schema{
+			"zone_id": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ExactlyOneOf: []string{"zone_id", "zone_name"},
+			},
+			"zone_name": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"type": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"name": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				ConflictsWith: []string{"name_regex"},
+			},
+			"name_regex": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringIsValidRegExp,
+			},
+			"value": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"records": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"type": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"value": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"ttl": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// recordFilter selects the records returned by the records data source. An
+// empty field matches all records.
+type recordFilter struct {
+	recordType string
+	name       string
+	nameRegex  *regexp.Regexp
+	value      string
+}
+
+func (f recordFilter) matches(record api.Record) bool {
+	if f.recordType != "" && !strings.EqualFold(f.recordType, record.Type) {
+		return false
+	}
+	if f.name != "" && f.name != record.Name {
+		return false
+	}
+	if f.nameRegex != nil && !f.nameRegex.MatchString(record.Name) {
+		return false
+	}
+	if f.value != "" && f.value != record.Value {
+		return false
+	}
+	return true
+}
+
+// dataSourceZoneID returns the zone_id of a data source or looks up the ID
+// of the zone with its zone_name.
+func dataSourceZoneID(c context.Context, d *schema.ResourceData, client *api.Client) (string, diag.Diagnostics) {
+	if zoneID := d.Get("zone_id").(string); zoneID != "" {
+		return zoneID, nil
+	}
+
+	zoneName := d.Get("zone_name").(string)
+	zone, err := client.GetZoneByName(c, zoneName)
+	if errors.Is(err, api.ErrNotFound) {
+		return "", diag.Errorf("DNS zone '%s' doesn't exist", zoneName)
+	} else if err != nil {
+		return "", diag.Errorf("Error getting zone %s: %s", zoneName, err)
+	}
+	return zone.ID, nil
+}
+
+func dataSourceRecordsRead(c context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	tflog.Debug(c, "Reading records")
+	client := m.(*api.Client)
+
+	zoneID, diags := dataSourceZoneID(c, d, client)
+	if diags.HasError() {
+		return diags
+	}
+
+	filter := recordFilter{
+		recordType: d.Get("type").(string),
+		name:       d.Get("name").(string),
+		value:      d.Get("value").(string),
+	}
+	if nameRegex := d.Get("name_regex").(string); nameRegex != "" {
+		// The regular expression is validated by the schema already
+		filter.nameRegex = regexp.MustCompile(nameRegex)
+	}
+
+	records, err := client.ListRecords(c, zoneID, api.ListRecordsOpts{})
+	if err != nil {
+		return diag.Errorf("Error getting records of zone %s: %s", zoneID, err)
+	}
+
+	result := make([]map[string]interface{}, 0, len(records))
+	for _, record := range records {
+		if !filter.matches(record) {
+			continue
+		}
+		ttl := 0
+		if record.HasTTL() {
+			ttl = *record.TTL
+		}
+		result = append(result, map[string]interface{}{
+			"id":    record.ID,
+			"name":  record.Name,
+			"type":  record.Type,
+			"value": record.Value,
+			"ttl":   ttl,
+		})
+	}
+	tflog.Debug(c, "Filtered records", "zone_id", zoneID, "records", len(records), "matching_records", len(result))
+
+	if err := d.Set("records", result); err != nil {
+		return diag.FromErr(err)
+	}
+	d.Set("zone_id", zoneID)
+	d.SetId(zoneID)
+
+	return nil
+}
@@ -0,0 +1,145 @@
+package hetznerdns
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/stretchr/testify/assert"
+	"github.com/timohirt/terraform-provider-hetznerdns/hetznerdns/api"
+)
+
+func TestAccRecordsDataSource(t *testing.T) {
+	// aZoneName must be a valid DNS domain name with an existing TLD
+	aZoneName := fmt.Sprintf("%s.online", acctest.RandString(10))
+	aZoneTTL := 60
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccAPITokenPresent(t) },
+		ProviderFactories: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccRecordsDataSourceConfig(aZoneName, aZoneTTL),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(
+						"data.hetznerdns_records.a", "records.#", "2"),
+					resource.TestCheckResourceAttrPair(
+						"data.hetznerdns_records.a", "zone_id",
+						"hetznerdns_zone.zone1", "id"),
+					resource.TestCheckResourceAttr(
+						"data.hetznerdns_records.www", "records.#", "1"),
+					resource.TestCheckResourceAttrPair(
+						"data.hetznerdns_records.www", "records.0.id",
+						"hetznerdns_record.www", "id"),
+					resource.TestCheckResourceAttr(
+						"data.hetznerdns_records.www", "records.0.name", "www"),
+					resource.TestCheckResourceAttr(
+						"data.hetznerdns_records.www", "records.0.type", "A"),
+					resource.TestCheckResourceAttr(
+						"data.hetznerdns_records.www", "records.0.value", "192.0.2.1"),
+					resource.TestCheckResourceAttr(
+						"data.hetznerdns_records.www", "records.0.ttl", "120"),
+					resource.TestCheckResourceAttr(
+						"data.hetznerdns_records.by_zone_name", "records.#", "2"),
+					resource.TestCheckResourceAttrPair(
+						"data.hetznerdns_records.by_zone_name", "zone_id",
+						"hetznerdns_zone.zone1", "id"),
+					resource.TestCheckResourceAttr(
+						"data.hetznerdns_records.mail_value", "records.#", "1"),
+					resource.TestCheckResourceAttr(
+						"data.hetznerdns_records.mail_value", "records.0.ttl", "0"),
+				),
+			},
+		},
+	})
+}
+
+func testAccRecordsDataSourceConfig(aZoneName string, aZoneTTL int) string {
+	return fmt.Sprintf(`
+resource "hetznerdns_zone" "zone1" {
+	name = "%s"
+	ttl = %d
+}
+
+resource "hetznerdns_record" "www" {
+	zone_id = hetznerdns_zone.zone1.id
+	name = "www"
+	type = "A"
+	value = "192.0.2.1"
+	ttl = 120
+}
+
+resource "hetznerdns_record" "mail" {
+	zone_id = hetznerdns_zone.zone1.id
+	name = "mail"
+	type = "A"
+	value = "192.0.2.2"
+}
+
+resource "hetznerdns_record" "mail_v6" {
+	zone_id = hetznerdns_zone.zone1.id
+	name = "mail"
+	type = "AAAA"
+	value = "2001:db8::2"
+}
+
+data "hetznerdns_records" "a" {
+	zone_id = hetznerdns_zone.zone1.id
+	type = "A"
+
+	depends_on = [hetznerdns_record.www, hetznerdns_record.mail, hetznerdns_record.mail_v6]
+}
+
+data "hetznerdns_records" "www" {
+	zone_id = hetznerdns_zone.zone1.id
+	name = "www"
+
+	depends_on = [hetznerdns_record.www, hetznerdns_record.mail, hetznerdns_record.mail_v6]
+}
+
+data "hetznerdns_records" "by_zone_name" {
+	zone_name = hetznerdns_zone.zone1.name
+	name_regex = "^mail"
+
+	depends_on = [hetznerdns_record.www, hetznerdns_record.mail, hetznerdns_record.mail_v6]
+}
+
+data "hetznerdns_records" "mail_value" {
+	zone_id = hetznerdns_zone.zone1.id
+	value = "192.0.2.2"
+
+	depends_on = [hetznerdns_record.www, hetznerdns_record.mail, hetznerdns_record.mail_v6]
+}
+`, aZoneName, aZoneTTL)
+}
+
+func TestRecordFilterMatches(t *testing.T) {
+	www := api.Record{Name: "www", Type: "A", Value: "192.0.2.1"}
+	wwwV6 := api.Record{Name: "www", Type: "AAAA", Value: "2001:db8::1"}
+	mail := api.Record{Name: "mail", Type: "A", Value: "192.0.2.2"}
+	records := []api.Record{www, wwwV6, mail}
+
+	tests := []struct {
+		filter   recordFilter
+		expected []api.Record
+	}{
+		{recordFilter{}, records},
+		{recordFilter{recordType: "a"}, []api.Record{www, mail}},
+		{recordFilter{name: "www"}, []api.Record{www, wwwV6}},
+		{recordFilter{name: "ww"}, nil},
+		{recordFilter{nameRegex: regexp.MustCompile("^(www|mail)$"), recordType: "A"}, []api.Record{www, mail}},
+		{recordFilter{value: "192.0.2.2"}, []api.Record{mail}},
+	}
+
+	for _, test := range tests {
+		var matching []api.Record
+		for _, record := range records {
+			if test.filter.matches(record) {
+				matching = append(matching, record)
+			}
+		}
+		assert.Equal(t, test.expected, matching, "filter %+v", test.filter)
+	}
+}
@@ -1,48 +1,64 @@
 package hetznerdns
 
 import (
-	"fmt"
-
-	"github.com/timohirt/terraform-provider-hetznerdns/hetznerdns/api"
+	"context"
+	"errors"
 
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/timohirt/terraform-provider-hetznerdns/hetznerdns/api"
 )
 
 func dataSourceHetznerDNSZone() *schema.Resource {
 	return &schema.Resource{
-		Read: dataSourceHetznerDNSZoneRead,
-		Schema: map[string]*schema.Schema{
+		ReadContext: dataSourceHetznerDNSZoneRead,
+		Schema: mergeSchemas(map[string]*schema.Schema{
+			"id": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ExactlyOneOf: []string{"id", "name"},
+			},
 			"name": {
 				Type:     schema.TypeString,
-				Required: true,
+				Optional: true,
+				Computed: true,
 			},
 			"ttl": {
 				Type:     schema.TypeInt,
 				Computed: true,
 			},
-		},
+		}, zoneComputedSchema()),
 	}
 }
 
-func dataSourceHetznerDNSZoneRead(d *schema.ResourceData, m interface{}) error {
+func dataSourceHetznerDNSZoneRead(c context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	client := m.(*api.Client)
-	name, isNonZeroName := d.GetOk("name")
-	if !isNonZeroName {
-		return fmt.Errorf("Data source zone has no 'name' set")
-	}
 
-	zone, err := client.GetZoneByName(name.(string))
-	if err != nil {
-		d.SetId("")
-		return fmt.Errorf("Error getting zone state. %s", err)
+	var zone *api.Zone
+	var err error
+	key := ""
+	if id := d.Get("id").(string); id != "" {
+		tflog.Debug(c, "Reading zone", "zone_id", id)
+		key = id
+		zone, err = client.GetZone(c, id)
+	} else {
+		name := d.Get("name").(string)
+		tflog.Debug(c, "Reading zone", "zone_name", name)
+		key = name
+		zone, err = client.GetZoneByName(c, name)
 	}
-
-	if zone == nil {
-		return fmt.Errorf("DNS zone '%s' doesn't exist", name.(string))
+	if errors.Is(err, api.ErrNotFound) {
+		return diag.Errorf("DNS zone '%s' doesn't exist", key)
+	}
+	if err != nil {
+		return diag.Errorf("Error getting zone %s: %s", key, err)
 	}
 
 	d.Set("name", zone.Name)
 	d.Set("ttl", zone.TTL)
+	setZoneComputedAttributes(d, zone)
 	d.SetId(zone.ID)
 
 	return nil
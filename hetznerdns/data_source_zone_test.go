@@ -31,6 +31,22 @@ func TestAccHcloudDataSourceDatasources(t *testing.T) {
 					resource.TestCheckResourceAttr(
 						"data.hetznerdns_zone.zone1", "ttl", strconv.Itoa(aTTL)),
 					resource.TestCheckResourceAttrSet("data.hetznerdns_zone.zone1", "id"),
+					resource.TestCheckResourceAttrSet("data.hetznerdns_zone.zone1", "ns.#"),
+					resource.TestCheckResourceAttrSet("data.hetznerdns_zone.zone1", "status"),
+					resource.TestCheckResourceAttrSet("data.hetznerdns_zone.zone1", "created"),
+					resource.TestCheckResourceAttrSet("data.hetznerdns_zone.zone1", "modified"),
+					resource.TestCheckResourceAttrSet("data.hetznerdns_zone.zone1", "records_count"),
+					resource.TestCheckResourceAttrPair(
+						"data.hetznerdns_zone.by_id", "name",
+						"hetznerdns_zone.zone1", "name"),
+					resource.TestCheckResourceAttr(
+						"data.hetznerdns_zone.by_id", "ttl", strconv.Itoa(aTTL)),
+					resource.TestCheckResourceAttrPair(
+						"data.hetznerdns_zone.by_id", "txt_verification.#",
+						"hetznerdns_zone.zone1", "txt_verification.#"),
+					resource.TestCheckResourceAttrPair(
+						"data.hetznerdns_zone.by_id", "status",
+						"data.hetznerdns_zone.zone1", "status"),
 				),
 			},
 		},
@@ -47,5 +63,9 @@ resource "hetznerdns_zone" "zone1" {
 data "hetznerdns_zone" "zone1" {
 	name = "${hetznerdns_zone.zone1.name}"
 }
+
+data "hetznerdns_zone" "by_id" {
+	id = hetznerdns_zone.zone1.id
+}
 `, name, ttl)
 }
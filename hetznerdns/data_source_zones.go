@@ -0,0 +1,112 @@
+package hetznerdns
+
+import (
+	"context"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/timohirt/terraform-provider-hetznerdns/hetznerdns/api"
+)
+
+func dataSourceZones() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceZonesRead,
+		Schema: map[string]*schema.Schema{
+			"search_name": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"name_regex": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringIsValidRegExp,
+			},
+			"zones": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"ttl": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"ns": {
+							Type:     schema.TypeList,
+							Computed: true,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+						},
+						"status": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"records_count": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceZonesRead(c context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	tflog.Debug(c, "Reading zones")
+	client := m.(*api.Client)
+
+	searchName := d.Get("search_name").(string)
+	zones, err := client.ListZones(c, api.ListZonesOpts{SearchName: searchName})
+	if err != nil {
+		return diag.Errorf("Error listing zones: %s", err)
+	}
+
+	// Sort the zones, so their order in the list only changes if zones
+	// are added or removed
+	sort.Slice(zones, func(i, j int) bool { return zones[i].Name < zones[j].Name })
+
+	var nameRegex *regexp.Regexp
+	if s := d.Get("name_regex").(string); s != "" {
+		// The regular expression is validated by the schema already
+		nameRegex = regexp.MustCompile(s)
+	}
+
+	result := make([]map[string]interface{}, 0, len(zones))
+	ids := make([]string, 0, len(zones))
+	for _, zone := range zones {
+		if nameRegex != nil && !nameRegex.MatchString(zone.Name) {
+			continue
+		}
+		result = append(result, map[string]interface{}{
+			"id":            zone.ID,
+			"name":          zone.Name,
+			"ttl":           zone.TTL,
+			"ns":            zone.NS,
+			"status":        zone.Status,
+			"records_count": zone.RecordsCount,
+		})
+		ids = append(ids, zone.ID)
+	}
+	tflog.Debug(c, "Filtered zones", "zones", len(zones), "matching_zones", len(result))
+
+	if err := d.Set("zones", result); err != nil {
+		return diag.FromErr(err)
+	}
+	// The ID changes whenever the set of matching zones does
+	d.SetId(strconv.Itoa(schema.HashString(strings.Join(ids, ","))))
+
+	return nil
+}
@@ -0,0 +1,77 @@
+package hetznerdns
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccZonesDataSource(t *testing.T) {
+	// The zone names share a random prefix, so other zones of the account
+	// don't match the search
+	prefix := acctest.RandString(10)
+	aZoneName := fmt.Sprintf("%s-a.online", prefix)
+	anotherZoneName := fmt.Sprintf("%s-b.online", prefix)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccAPITokenPresent(t) },
+		ProviderFactories: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccZonesDataSourceConfig(prefix, aZoneName, anotherZoneName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(
+						"data.hetznerdns_zones.all", "zones.#", "2"),
+					resource.TestCheckResourceAttrPair(
+						"data.hetznerdns_zones.all", "zones.0.id",
+						"hetznerdns_zone.a", "id"),
+					resource.TestCheckResourceAttr(
+						"data.hetznerdns_zones.all", "zones.0.name", aZoneName),
+					resource.TestCheckResourceAttr(
+						"data.hetznerdns_zones.all", "zones.0.ttl", "60"),
+					resource.TestCheckResourceAttrSet(
+						"data.hetznerdns_zones.all", "zones.0.ns.0"),
+					resource.TestCheckResourceAttrSet(
+						"data.hetznerdns_zones.all", "zones.0.status"),
+					resource.TestCheckResourceAttrSet(
+						"data.hetznerdns_zones.all", "zones.0.records_count"),
+					resource.TestCheckResourceAttr(
+						"data.hetznerdns_zones.b", "zones.#", "1"),
+					resource.TestCheckResourceAttr(
+						"data.hetznerdns_zones.b", "zones.0.name", anotherZoneName),
+					resource.TestCheckResourceAttr(
+						"data.hetznerdns_zones.b", "zones.0.ttl", "120"),
+				),
+			},
+		},
+	})
+}
+
+func testAccZonesDataSourceConfig(prefix string, aZoneName string, anotherZoneName string) string {
+	return fmt.Sprintf(`
+resource "hetznerdns_zone" "a" {
+	name = "%s"
+	ttl = 60
+}
+
+resource "hetznerdns_zone" "b" {
+	name = "%s"
+	ttl = 120
+}
+
+data "hetznerdns_zones" "all" {
+	search_name = "%s"
+
+	depends_on = [hetznerdns_zone.a, hetznerdns_zone.b]
+}
+
+data "hetznerdns_zones" "b" {
+	search_name = "%s"
+	name_regex = "-b\\.online$"
+
+	depends_on = [hetznerdns_zone.a, hetznerdns_zone.b]
+}
+`, aZoneName, anotherZoneName, prefix, prefix)
+}
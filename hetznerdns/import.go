@@ -0,0 +1,182 @@
+package hetznerdns
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/timohirt/terraform-provider-hetznerdns/hetznerdns/api"
+)
+
+// isZoneName reports whether zone is the name of a zone rather than its ID.
+// Zone names are domain names, while the IDs the API assigns never contain
+// a dot.
+func isZoneName(zone string) bool {
+	return strings.Contains(zone, ".")
+}
+
+// importZoneID returns the ID of zone, which is either the ID or the name
+// of a zone.
+func importZoneID(c context.Context, client *api.Client, zone string) (string, error) {
+	if !isZoneName(zone) {
+		return zone, nil
+	}
+
+	found, err := client.GetZoneByName(c, zone)
+	if err != nil {
+		return "", err
+	}
+	return found.ID, nil
+}
+
+// resourceZoneImport imports a zone by its ID or its name
+func resourceZoneImport(c context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	client := m.(*api.Client)
+
+	zoneID, err := importZoneID(c, client, d.Id())
+	if err != nil {
+		return nil, fmt.Errorf("Error importing zone %s: %s", d.Id(), err)
+	}
+	tflog.Debug(c, "Importing zone", "import_id", d.Id(), "id", zoneID)
+
+	d.SetId(zoneID)
+	return []*schema.ResourceData{d}, nil
+}
+
+// recordImportID is the parsed import ID of a record in the form
+// zone/name/TYPE[/value]
+type recordImportID struct {
+	zone       string
+	name       string
+	recordType string
+	value      string
+}
+
+// parseRecordImportID parses the import ID of a record. It returns false if
+// id is a plain record ID. The value may contain slashes, e.g. in TXT
+// records, so everything after the type is the value.
+func parseRecordImportID(id string) (recordImportID, bool, error) {
+	if !strings.Contains(id, "/") {
+		return recordImportID{}, false, nil
+	}
+
+	parts := strings.SplitN(id, "/", 4)
+	if len(parts) < 3 || parts[0] == "" || parts[1] == "" || parts[2] == "" {
+		return recordImportID{}, false, fmt.Errorf("Import ID '%s' of record is neither a record ID nor of the form zone/name/TYPE[/value]", id)
+	}
+
+	parsed := recordImportID{zone: parts[0], name: parts[1], recordType: parts[2]}
+	if len(parts) == 4 {
+		parsed.value = parts[3]
+	}
+	return parsed, true, nil
+}
+
+// resourceRecordImport imports a record by its ID or by
+// zone/name/TYPE[/value], where zone is the ID or the name of the zone. The
+// value only has to be given if the zone has several records with the same
+// name and type.
+func resourceRecordImport(c context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	client := m.(*api.Client)
+
+	parsed, ok, err := parseRecordImportID(d.Id())
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return []*schema.ResourceData{d}, nil
+	}
+
+	zoneID, err := importZoneID(c, client, parsed.zone)
+	if err != nil {
+		return nil, fmt.Errorf("Error importing record %s: %s", d.Id(), err)
+	}
+
+	record, err := client.LookupRecord(c, api.LookupRecordOpts{
+		ZoneID: zoneID,
+		Name:   parsed.name,
+		Type:   parsed.recordType,
+		Value:  parsed.value,
+	})
+	if errors.Is(err, api.ErrMultipleMatches) {
+		return nil, fmt.Errorf("Error importing record %s: %s. Add the value of the record to the import ID to select one of them", d.Id(), err)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("Error importing record %s: %s", d.Id(), err)
+	}
+	tflog.Debug(c, "Importing record", "import_id", d.Id(), "id", record.ID)
+
+	d.SetId(record.ID)
+	return []*schema.ResourceData{d}, nil
+}
+
+// primaryServerImportID is the parsed import ID of a primary server in the
+// form zone/address:port
+type primaryServerImportID struct {
+	zone    string
+	address string
+	port    int
+}
+
+// parsePrimaryServerImportID parses the import ID of a primary server. It
+// returns false if id is a plain primary server ID. IPv6 addresses have to
+// be enclosed in brackets, e.g. zone/[2001:db8::1]:53.
+func parsePrimaryServerImportID(id string) (primaryServerImportID, bool, error) {
+	if !strings.Contains(id, "/") {
+		return primaryServerImportID{}, false, nil
+	}
+
+	invalid := fmt.Errorf("Import ID '%s' of primary server is neither a primary server ID nor of the form zone/address:port", id)
+	parts := strings.SplitN(id, "/", 2)
+	if parts[0] == "" {
+		return primaryServerImportID{}, false, invalid
+	}
+	address, port, err := net.SplitHostPort(parts[1])
+	if err != nil || address == "" {
+		return primaryServerImportID{}, false, invalid
+	}
+	portInt, err := strconv.Atoi(port)
+	if err != nil {
+		return primaryServerImportID{}, false, invalid
+	}
+
+	return primaryServerImportID{zone: parts[0], address: address, port: portInt}, true, nil
+}
+
+// resourcePrimaryServerImport imports a primary server by its ID or by
+// zone/address:port, where zone is the ID or the name of the zone.
+func resourcePrimaryServerImport(c context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	client := m.(*api.Client)
+
+	parsed, ok, err := parsePrimaryServerImportID(d.Id())
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return []*schema.ResourceData{d}, nil
+	}
+
+	zoneID, err := importZoneID(c, client, parsed.zone)
+	if err != nil {
+		return nil, fmt.Errorf("Error importing primary server %s: %s", d.Id(), err)
+	}
+
+	primaryServers, err := client.ListPrimaryServers(c, zoneID)
+	if err != nil {
+		return nil, fmt.Errorf("Error importing primary server %s: %s", d.Id(), err)
+	}
+	for _, primaryServer := range primaryServers {
+		if primaryServer.Address == parsed.address && primaryServer.Port != nil && *primaryServer.Port == parsed.port {
+			tflog.Debug(c, "Importing primary server", "import_id", d.Id(), "id", primaryServer.ID)
+			d.SetId(primaryServer.ID)
+			return []*schema.ResourceData{d}, nil
+		}
+	}
+
+	return nil, fmt.Errorf("Error importing primary server %s: There is no primary server %s:%d in zone %s", d.Id(), parsed.address, parsed.port, zoneID)
+}
@@ -0,0 +1,53 @@
+package hetznerdns
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseRecordImportID(t *testing.T) {
+	tests := []struct {
+		id       string
+		expected recordImportID
+		parsed   bool
+		valid    bool
+	}{
+		{"ed2416cb6bc8a8055b22222", recordImportID{}, false, true},
+		{"example.com/www/A", recordImportID{zone: "example.com", name: "www", recordType: "A"}, true, true},
+		{"rMu2waTJPbHr4/@/MX/10 mail.example.com.", recordImportID{zone: "rMu2waTJPbHr4", name: "@", recordType: "MX", value: "10 mail.example.com."}, true, true},
+		{"example.com/@/TXT/v=spf1 a/24 -all", recordImportID{zone: "example.com", name: "@", recordType: "TXT", value: "v=spf1 a/24 -all"}, true, true},
+		{"example.com/www", recordImportID{}, false, false},
+		{"example.com//A", recordImportID{}, false, false},
+	}
+
+	for _, test := range tests {
+		parsed, ok, err := parseRecordImportID(test.id)
+		assert.Equal(t, test.valid, err == nil, "import ID %s", test.id)
+		assert.Equal(t, test.parsed, ok, "import ID %s", test.id)
+		assert.Equal(t, test.expected, parsed, "import ID %s", test.id)
+	}
+}
+
+func TestParsePrimaryServerImportID(t *testing.T) {
+	tests := []struct {
+		id       string
+		expected primaryServerImportID
+		parsed   bool
+		valid    bool
+	}{
+		{"a1b2c3d4", primaryServerImportID{}, false, true},
+		{"example.com/192.0.2.1:53", primaryServerImportID{zone: "example.com", address: "192.0.2.1", port: 53}, true, true},
+		{"rMu2waTJPbHr4/[2001:db8::1]:5353", primaryServerImportID{zone: "rMu2waTJPbHr4", address: "2001:db8::1", port: 5353}, true, true},
+		{"example.com/192.0.2.1", primaryServerImportID{}, false, false},
+		{"example.com/192.0.2.1:dns", primaryServerImportID{}, false, false},
+		{"/192.0.2.1:53", primaryServerImportID{}, false, false},
+	}
+
+	for _, test := range tests {
+		parsed, ok, err := parsePrimaryServerImportID(test.id)
+		assert.Equal(t, test.valid, err == nil, "import ID %s", test.id)
+		assert.Equal(t, test.parsed, ok, "import ID %s", test.id)
+		assert.Equal(t, test.expected, parsed, "import ID %s", test.id)
+	}
+}
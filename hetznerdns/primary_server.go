@@ -2,10 +2,11 @@ package hetznerdns
 
 import (
 	"context"
+	"errors"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/timohirt/terraform-provider-hetznerdns/hetznerdns/api"
-	"log"
 )
 
 func resourcePrimaryServer() *schema.Resource {
@@ -15,7 +16,7 @@ func resourcePrimaryServer() *schema.Resource {
 		UpdateContext: resourcePrimaryServerUpdate,
 		DeleteContext: resourcePrimaryServerDelete,
 		Importer: &schema.ResourceImporter{
-			StateContext: schema.ImportStatePassthroughContext,
+			StateContext: resourcePrimaryServerImport,
 		},
 
 		Schema: map[string]*schema.Schema{
@@ -37,7 +38,7 @@ func resourcePrimaryServer() *schema.Resource {
 }
 
 func resourcePrimaryServerCreate(c context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	log.Printf("[DEBUG] Creating primary server")
+	tflog.Debug(c, "Creating primary server")
 	client := m.(*api.Client)
 
 	zoneID, zoneIDNonEmpty := d.GetOk("zone_id")
@@ -62,9 +63,9 @@ func resourcePrimaryServerCreate(c context.Context, d *schema.ResourceData, m in
 		Port:    &portInt,
 	}
 
-	record, err := client.CreatePrimaryServer(opts)
+	record, err := client.CreatePrimaryServer(c, opts)
 	if err != nil {
-		log.Printf("[ERROR] Error creating primary server %s: %s", opts.Address, err)
+		tflog.Error(c, "Error creating primary server", "address", opts.Address, "error", err.Error())
 		return diag.Errorf("Error creating primary server %s: %s", opts.Address, err)
 	}
 
@@ -73,20 +74,19 @@ func resourcePrimaryServerCreate(c context.Context, d *schema.ResourceData, m in
 }
 
 func resourcePrimaryServerRead(c context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	log.Printf("[DEBUG] Reading primary server")
+	tflog.Debug(c, "Reading primary server")
 	client := m.(*api.Client)
 
 	id := d.Id()
-	record, err := client.GetPrimaryServer(id)
-	if err != nil {
-		return diag.Errorf("Error getting primary server with id %s: %s", id, err)
-	}
-
-	if record == nil {
-		log.Printf("[WARN] Primary server with id %s doesn't exist, removing it from state", id)
+	record, err := client.GetPrimaryServer(c, id)
+	if errors.Is(err, api.ErrNotFound) {
+		tflog.Warn(c, "Primary server doesn't exist, removing it from state", "id", id)
 		d.SetId("")
 		return nil
 	}
+	if err != nil {
+		return diag.Errorf("Error getting primary server with id %s: %s", id, err)
+	}
 
 	d.SetId(record.ID)
 	d.Set("address", record.Address)
@@ -97,27 +97,26 @@ func resourcePrimaryServerRead(c context.Context, d *schema.ResourceData, m inte
 }
 
 func resourcePrimaryServerUpdate(c context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	log.Printf("[DEBUG] Updating primary server")
+	tflog.Debug(c, "Updating primary server")
 	client := m.(*api.Client)
 
 	id := d.Id()
-	record, err := client.GetPrimaryServer(id)
-	if err != nil {
-		return diag.Errorf("Error getting primary server with id %s: %s", id, err)
-	}
-
-	if record == nil {
-		log.Printf("[WARN] Primary server with id %s doesn't exist, removing it from state", id)
+	record, err := client.GetPrimaryServer(c, id)
+	if errors.Is(err, api.ErrNotFound) {
+		tflog.Warn(c, "Primary server doesn't exist, removing it from state", "id", id)
 		d.SetId("")
 		return nil
 	}
+	if err != nil {
+		return diag.Errorf("Error getting primary server with id %s: %s", id, err)
+	}
 
 	if d.HasChanges("address", "port") {
 		record.Address = d.Get("address").(string)
 		port := d.Get("port").(int)
 		record.Port = &port
 
-		record, err = client.UpdatePrimaryServer(*record)
+		record, err = client.UpdatePrimaryServer(c, *record)
 		if err != nil {
 			return diag.FromErr(err)
 		}
@@ -127,14 +126,14 @@ func resourcePrimaryServerUpdate(c context.Context, d *schema.ResourceData, m in
 }
 
 func resourcePrimaryServerDelete(c context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	log.Printf("[DEBUG] Deleting resource record")
+	tflog.Debug(c, "Deleting primary server")
 
 	client := m.(*api.Client)
 	recordID := d.Id()
 
-	err := client.DeletePrimaryServer(recordID)
+	err := client.DeletePrimaryServer(c, recordID)
 	if err != nil {
-		log.Printf("[ERROR] Error deleting primary server %s: %s", recordID, err)
+		tflog.Error(c, "Error deleting primary server", "id", recordID, "error", err.Error())
 		return diag.FromErr(err)
 	}
 
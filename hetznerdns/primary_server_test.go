@@ -1,12 +1,14 @@
 package hetznerdns
 
 import (
+	"context"
 	"fmt"
 	"strconv"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/timohirt/terraform-provider-hetznerdns/hetznerdns/api"
 )
 
 const validPublicIpAddress = "167.182.9.17"
@@ -18,6 +20,8 @@ func TestAccPrimaryServerResources(t *testing.T) {
 
 	psAddress := validPublicIpAddress
 	psPort := 53
+	anotherPSPort := 5353
+	var psID string
 
 	resource.Test(t, resource.TestCase{
 		PreCheck:          func() { testAccAPITokenPresent(t) },
@@ -33,6 +37,39 @@ func TestAccPrimaryServerResources(t *testing.T) {
 						"hetznerdns_primary_server.ps1", "address", psAddress),
 					resource.TestCheckResourceAttr(
 						"hetznerdns_primary_server.ps1", "port", strconv.Itoa(psPort)),
+					testAccCaptureID("hetznerdns_primary_server.ps1", &psID),
+				),
+			},
+			{
+				Config: testAccPrimaryServerResourceConfigCreate(aZoneName, aZoneTTL, psAddress, anotherPSPort),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPtr(
+						"hetznerdns_primary_server.ps1", "id", &psID),
+					resource.TestCheckResourceAttr(
+						"hetznerdns_primary_server.ps1", "port", strconv.Itoa(anotherPSPort)),
+				),
+			},
+			{
+				ResourceName:      "hetznerdns_primary_server.ps1",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			{
+				ResourceName:      "hetznerdns_primary_server.ps1",
+				ImportState:       true,
+				ImportStateId:     fmt.Sprintf("%s/%s:%d", aZoneName, psAddress, anotherPSPort),
+				ImportStateVerify: true,
+			},
+			{
+				// The primary server was deleted outside of Terraform
+				PreConfig: testAccChangeOutside(t, func(ctx context.Context, client *api.Client) error {
+					return client.DeletePrimaryServer(ctx, psID)
+				}),
+				Config: testAccPrimaryServerResourceConfigCreate(aZoneName, aZoneTTL, psAddress, anotherPSPort),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet("hetznerdns_primary_server.ps1", "id"),
+					resource.TestCheckResourceAttr(
+						"hetznerdns_primary_server.ps1", "port", strconv.Itoa(anotherPSPort)),
 				),
 			},
 		},
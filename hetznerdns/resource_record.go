@@ -2,11 +2,12 @@ package hetznerdns
 
 import (
 	"context"
+	"errors"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
-	"log"
 
 	"github.com/timohirt/terraform-provider-hetznerdns/hetznerdns/api"
 
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
@@ -17,7 +18,7 @@ func resourceRecord() *schema.Resource {
 		UpdateContext: resourceRecordUpdate,
 		DeleteContext: resourceRecordDelete,
 		Importer: &schema.ResourceImporter{
-			StateContext: schema.ImportStatePassthroughContext,
+			StateContext: resourceRecordImport,
 		},
 
 		Schema: map[string]*schema.Schema{
@@ -47,7 +48,7 @@ func resourceRecord() *schema.Resource {
 }
 
 func resourceRecordCreate(c context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	log.Printf("[DEBUG] Updating resource record")
+	tflog.Debug(c, "Creating resource record")
 	client := m.(*api.Client)
 
 	zoneID, zoneIDNonEmpty := d.GetOk("zone_id")
@@ -83,9 +84,9 @@ func resourceRecordCreate(c context.Context, d *schema.ResourceData, m interface
 		opts.TTL = &nonEmptyTTL
 	}
 
-	record, err := client.CreateRecord(opts)
+	record, err := client.CreateRecord(c, opts)
 	if err != nil {
-		log.Printf("[ERROR] Error creating DNS record %s: %s", opts.Name, err)
+		tflog.Error(c, "Error creating DNS record", "name", opts.Name, "error", err.Error())
 		return diag.Errorf("Error creating DNS record %s: %s", opts.Name, err)
 	}
 
@@ -94,20 +95,19 @@ func resourceRecordCreate(c context.Context, d *schema.ResourceData, m interface
 }
 
 func resourceRecordRead(c context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	log.Printf("[DEBUG] Reading resource record")
+	tflog.Debug(c, "Reading resource record")
 	client := m.(*api.Client)
 
 	id := d.Id()
-	record, err := client.GetRecord(id)
-	if err != nil {
-		return diag.Errorf("Error getting record with id %s: %s", id, err)
-	}
-
-	if record == nil {
-		log.Printf("[WARN] DNS record with id %s doesn't exist, removing it from state", id)
+	record, err := client.GetRecord(c, id)
+	if errors.Is(err, api.ErrNotFound) {
+		tflog.Warn(c, "DNS record doesn't exist, removing it from state", "id", id)
 		d.SetId("")
 		return nil
 	}
+	if err != nil {
+		return diag.Errorf("Error getting record with id %s: %s", id, err)
+	}
 
 	d.SetId(record.ID)
 	d.Set("name", record.Name)
@@ -124,20 +124,19 @@ func resourceRecordRead(c context.Context, d *schema.ResourceData, m interface{}
 }
 
 func resourceRecordUpdate(c context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	log.Printf("[DEBUG] Updating resource record")
+	tflog.Debug(c, "Updating resource record")
 	client := m.(*api.Client)
 
 	id := d.Id()
-	record, err := client.GetRecord(id)
-	if err != nil {
-		return diag.Errorf("Error getting record with id %s: %s", id, err)
-	}
-
-	if record == nil {
-		log.Printf("[WARN] DNS record with id %s doesn't exist, removing it from state", id)
+	record, err := client.GetRecord(c, id)
+	if errors.Is(err, api.ErrNotFound) {
+		tflog.Warn(c, "DNS record doesn't exist, removing it from state", "id", id)
 		d.SetId("")
 		return nil
 	}
+	if err != nil {
+		return diag.Errorf("Error getting record with id %s: %s", id, err)
+	}
 
 	if d.HasChanges("name", "ttl", "type", "value") {
 		record.Name = d.Get("name").(string)
@@ -151,7 +150,7 @@ func resourceRecordUpdate(c context.Context, d *schema.ResourceData, m interface
 		record.Type = d.Get("type").(string)
 		record.Value = d.Get("value").(string)
 
-		record, err = client.UpdateRecord(*record)
+		record, err = client.UpdateRecord(c, *record)
 		if err != nil {
 			return diag.FromErr(err)
 		}
@@ -161,14 +160,14 @@ func resourceRecordUpdate(c context.Context, d *schema.ResourceData, m interface
 }
 
 func resourceRecordDelete(c context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	log.Printf("[DEBUG] Deleting resource record")
+	tflog.Debug(c, "Deleting resource record")
 
 	client := m.(*api.Client)
 	recordID := d.Id()
 
-	err := client.DeleteRecord(recordID)
+	err := client.DeleteRecord(c, recordID)
 	if err != nil {
-		log.Printf("[ERROR] Error deleting record %s: %s", recordID, err)
+		tflog.Error(c, "Error deleting record", "id", recordID, "error", err.Error())
 		return diag.FromErr(err)
 	}
 
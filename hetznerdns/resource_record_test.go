@@ -1,12 +1,15 @@
 package hetznerdns
 
 import (
+	"context"
 	"fmt"
 	"strconv"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/timohirt/terraform-provider-hetznerdns/hetznerdns/api"
 )
 
 func TestAccRecordResources(t *testing.T) {
@@ -18,6 +21,8 @@ func TestAccRecordResources(t *testing.T) {
 	aName := acctest.RandString(10)
 	aType := "A"
 	aTTL := aZoneTTL * 2
+	anotherValue := "192.168.1.2"
+	var recordID string
 
 	resource.Test(t, resource.TestCase{
 		PreCheck:          func() { testAccAPITokenPresent(t) },
@@ -37,6 +42,60 @@ func TestAccRecordResources(t *testing.T) {
 						"hetznerdns_record.record1", "value", aValue),
 					resource.TestCheckResourceAttr(
 						"hetznerdns_record.record1", "ttl", strconv.Itoa(aTTL)),
+					testAccCaptureID("hetznerdns_record.record1", &recordID),
+				),
+			},
+			{
+				Config: testAccRecordResourceConfigCreate(aZoneName, aZoneTTL, aName, aType, anotherValue, aTTL),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPtr(
+						"hetznerdns_record.record1", "id", &recordID),
+					resource.TestCheckResourceAttr(
+						"hetznerdns_record.record1", "value", anotherValue),
+				),
+			},
+			{
+				ResourceName:      "hetznerdns_record.record1",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			{
+				ResourceName:      "hetznerdns_record.record1",
+				ImportState:       true,
+				ImportStateId:     fmt.Sprintf("%s/%s/%s", aZoneName, aName, aType),
+				ImportStateVerify: true,
+			},
+			{
+				ResourceName:      "hetznerdns_record.record1",
+				ImportState:       true,
+				ImportStateIdFunc: testAccRecordImportIDWithZoneID("hetznerdns_record.record1", anotherValue),
+				ImportStateVerify: true,
+			},
+			{
+				// The value was changed outside of Terraform
+				PreConfig: testAccChangeOutside(t, func(ctx context.Context, client *api.Client) error {
+					record, err := client.GetRecord(ctx, recordID)
+					if err != nil {
+						return err
+					}
+					record.Value = aValue
+					_, err = client.UpdateRecord(ctx, *record)
+					return err
+				}),
+				Config: testAccRecordResourceConfigCreate(aZoneName, aZoneTTL, aName, aType, anotherValue, aTTL),
+				Check: resource.TestCheckResourceAttr(
+					"hetznerdns_record.record1", "value", anotherValue),
+			},
+			{
+				// The record was deleted outside of Terraform
+				PreConfig: testAccChangeOutside(t, func(ctx context.Context, client *api.Client) error {
+					return client.DeleteRecord(ctx, recordID)
+				}),
+				Config: testAccRecordResourceConfigCreate(aZoneName, aZoneTTL, aName, aType, anotherValue, aTTL),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet("hetznerdns_record.record1", "id"),
+					resource.TestCheckResourceAttr(
+						"hetznerdns_record.record1", "value", anotherValue),
 				),
 			},
 		},
@@ -60,6 +119,19 @@ resource "hetznerdns_record" "record1" {
 `, aZoneName, aZoneTTL, aType, aName, aValue, aTTL)
 }
 
+// testAccRecordImportIDWithZoneID returns the import ID of a record in the
+// form zone_id/name/TYPE/value.
+func testAccRecordImportIDWithZoneID(name string, value string) resource.ImportStateIdFunc {
+	return func(s *terraform.State) (string, error) {
+		rs, ok := s.RootModule().Resources[name]
+		if !ok {
+			return "", fmt.Errorf("resource %s not found in state", name)
+		}
+		attributes := rs.Primary.Attributes
+		return fmt.Sprintf("%s/%s/%s/%s", attributes["zone_id"], attributes["name"], attributes["type"], value), nil
+	}
+}
+
 func TestAccRecordWithDefaultTTLResources(t *testing.T) {
 	// aZoneName must be a valid DNS domain name with an existing TLD
 	aZoneName := fmt.Sprintf("%s.online", acctest.RandString(10))
@@ -2,11 +2,12 @@ package hetznerdns
 
 import (
 	"context"
+	"errors"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
-	"log"
 
 	"github.com/timohirt/terraform-provider-hetznerdns/hetznerdns/api"
 
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
@@ -17,10 +18,10 @@ func resourceZone() *schema.Resource {
 		UpdateContext: resourceZoneUpdate,
 		DeleteContext: resourceZoneDelete,
 		Importer: &schema.ResourceImporter{
-			StateContext: schema.ImportStatePassthroughContext,
+			StateContext: resourceZoneImport,
 		},
 
-		Schema: map[string]*schema.Schema{
+		Schema: mergeSchemas(map[string]*schema.Schema{
 			"name": {
 				Type:     schema.TypeString,
 				Required: true,
@@ -30,12 +31,129 @@ func resourceZone() *schema.Resource {
 				Type:     schema.TypeInt,
 				Required: true,
 			},
+		}, zoneComputedSchema()),
+	}
+}
+
+// zoneComputedSchema returns the read-only attributes of a zone shared by
+// the hetznerdns_zone resource and data source.
+func zoneComputedSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"ns": {
+			Type:     schema.TypeList,
+			Computed: true,
+			Elem:     &schema.Schema{Type: schema.TypeString},
+		},
+		"legacy_ns": {
+			Type:     schema.TypeList,
+			Computed: true,
+			Elem:     &schema.Schema{Type: schema.TypeString},
+		},
+		"legacy_dns_host": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"registrar": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"owner": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"project": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"permission": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"status": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"verified": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"created": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"modified": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"paused": {
+			Type:     schema.TypeBool,
+			Computed: true,
+		},
+		"is_secondary_dns": {
+			Type:     schema.TypeBool,
+			Computed: true,
+		},
+		"records_count": {
+			Type:     schema.TypeInt,
+			Computed: true,
+		},
+		"txt_verification": {
+			Type:     schema.TypeList,
+			Computed: true,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"name": {
+						Type:     schema.TypeString,
+						Computed: true,
+					},
+					"token": {
+						Type:     schema.TypeString,
+						Computed: true,
+					},
+				},
+			},
 		},
 	}
 }
 
+// setZoneComputedAttributes sets the attributes of zoneComputedSchema
+func setZoneComputedAttributes(d *schema.ResourceData, zone *api.Zone) {
+	d.Set("ns", zone.NS)
+	d.Set("legacy_ns", zone.LegacyNS)
+	d.Set("legacy_dns_host", zone.LegacyDNSHost)
+	d.Set("registrar", zone.Registrar)
+	d.Set("owner", zone.Owner)
+	d.Set("project", zone.Project)
+	d.Set("permission", zone.Permission)
+	d.Set("status", zone.Status)
+	d.Set("verified", zone.Verified)
+	d.Set("created", zone.Created)
+	d.Set("modified", zone.Modified)
+	d.Set("paused", zone.Paused)
+	d.Set("is_secondary_dns", zone.IsSecondaryDNS)
+	d.Set("records_count", zone.RecordsCount)
+
+	d.Set("txt_verification", nil)
+	if zone.TxtVerification != nil {
+		d.Set("txt_verification", []map[string]interface{}{{
+			"name":  zone.TxtVerification.Name,
+			"token": zone.TxtVerification.Token,
+		}})
+	}
+}
+
+func mergeSchemas(schemas ...map[string]*schema.Schema) map[string]*schema.Schema {
+	merged := map[string]*schema.Schema{}
+	for _, s := range schemas {
+		for k, v := range s {
+			merged[k] = v
+		}
+	}
+	return merged
+}
+
 func resourceZoneCreate(c context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	log.Printf("[DEBUG] Creating resource zone")
+	tflog.Debug(c, "Creating resource zone")
 
 	client := m.(*api.Client)
 
@@ -48,9 +166,9 @@ func resourceZoneCreate(c context.Context, d *schema.ResourceData, m interface{}
 		opts.TTL = ttl.(int)
 	}
 
-	resp, err := client.CreateZone(opts)
+	resp, err := client.CreateZone(c, opts)
 	if err != nil {
-		log.Printf("[ERROR] Creating resource zone failed: %s", err)
+		tflog.Error(c, "Creating resource zone failed", "error", err.Error())
 		d.SetId("")
 		return diag.FromErr(err)
 	}
@@ -60,46 +178,45 @@ func resourceZoneCreate(c context.Context, d *schema.ResourceData, m interface{}
 }
 
 func resourceZoneRead(c context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	log.Printf("[DEBUG] Reading resource zone")
+	tflog.Debug(c, "Reading resource zone")
 	client := m.(*api.Client)
 	zoneID := d.Id()
-	zone, err := client.GetZone(zoneID)
-	if err != nil {
-		log.Printf("[ERROR] Reading resource zone failed: %s", err)
-		return diag.FromErr(err)
-	}
-
-	if zone == nil {
-		log.Printf("[WARN] DNS zone with id %s doesn't exist, removing it from state", zoneID)
+	zone, err := client.GetZone(c, zoneID)
+	if errors.Is(err, api.ErrNotFound) {
+		tflog.Warn(c, "DNS zone doesn't exist, removing it from state", "id", zoneID)
 		d.SetId("")
 		return nil
 	}
+	if err != nil {
+		tflog.Error(c, "Reading resource zone failed", "error", err.Error())
+		return diag.FromErr(err)
+	}
 
 	d.Set("name", zone.Name)
 	d.Set("ttl", zone.TTL)
+	setZoneComputedAttributes(d, zone)
 
 	return nil
 }
 
 func resourceZoneUpdate(c context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	log.Printf("[DEBUG] Updating resource zone")
+	tflog.Debug(c, "Updating resource zone")
 	client := m.(*api.Client)
 	zoneID := d.Id()
-	zone, err := client.GetZone(zoneID)
-	if err != nil {
-		return diag.FromErr(err)
-	}
-
-	if zone == nil {
-		log.Printf("[WARN] DNS zone with id %s doesn't exist, removing it from state", zoneID)
+	zone, err := client.GetZone(c, zoneID)
+	if errors.Is(err, api.ErrNotFound) {
+		tflog.Warn(c, "DNS zone doesn't exist, removing it from state", "id", zoneID)
 		d.SetId("")
 		return nil
 	}
+	if err != nil {
+		return diag.FromErr(err)
+	}
 
 	d.Partial(true)
 	if d.HasChange("ttl") {
 		zone.TTL = d.Get("ttl").(int)
-		zone, err = client.UpdateZone(*zone)
+		zone, err = client.UpdateZone(c, *zone)
 		if err != nil {
 			return diag.FromErr(err)
 		}
@@ -111,14 +228,14 @@ func resourceZoneUpdate(c context.Context, d *schema.ResourceData, m interface{}
 }
 
 func resourceZoneDelete(c context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	log.Printf("[DEBUG] Deleting resource zone")
+	tflog.Debug(c, "Deleting resource zone")
 
 	client := m.(*api.Client)
 	zoneID := d.Id()
 
-	err := client.DeleteZone(zoneID)
+	err := client.DeleteZone(c, zoneID)
 	if err != nil {
-		log.Printf("[ERROR] Error deleting zone %s: %s", zoneID, err)
+		tflog.Error(c, "Error deleting zone", "id", zoneID, "error", err.Error())
 		return diag.FromErr(err)
 	}
 
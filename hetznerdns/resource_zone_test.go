@@ -1,12 +1,14 @@
 package hetznerdns
 
 import (
+	"context"
 	"fmt"
 	"strconv"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/timohirt/terraform-provider-hetznerdns/hetznerdns/api"
 )
 
 func init() {
@@ -19,6 +21,8 @@ func TestAccZoneResources(t *testing.T) {
 	// aName must be a valid DNS domain name with an existing TLD
 	aName := fmt.Sprintf("%s.online", acctest.RandString(10))
 	aTTL := 60
+	anotherTTL := 120
+	var zoneID string
 
 	resource.Test(t, resource.TestCase{
 		PreCheck:          func() { testAccAPITokenPresent(t) },
@@ -32,6 +36,52 @@ func TestAccZoneResources(t *testing.T) {
 						"hetznerdns_zone.zone1", "name", aName),
 					resource.TestCheckResourceAttr(
 						"hetznerdns_zone.zone1", "ttl", strconv.Itoa(aTTL)),
+					resource.TestCheckResourceAttrSet("hetznerdns_zone.zone1", "ns.#"),
+					resource.TestCheckResourceAttrSet("hetznerdns_zone.zone1", "status"),
+					resource.TestCheckResourceAttrSet("hetznerdns_zone.zone1", "created"),
+					testAccCaptureID("hetznerdns_zone.zone1", &zoneID),
+				),
+			},
+			{
+				Config: testAccZoneResourceConfigCreate(aName, anotherTTL),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPtr(
+						"hetznerdns_zone.zone1", "id", &zoneID),
+					resource.TestCheckResourceAttr(
+						"hetznerdns_zone.zone1", "ttl", strconv.Itoa(anotherTTL)),
+				),
+			},
+			{
+				ResourceName:      "hetznerdns_zone.zone1",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			{
+				ResourceName:      "hetznerdns_zone.zone1",
+				ImportState:       true,
+				ImportStateId:     aName,
+				ImportStateVerify: true,
+			},
+			{
+				// The TTL was changed outside of Terraform
+				PreConfig: testAccChangeOutside(t, func(ctx context.Context, client *api.Client) error {
+					_, err := client.UpdateZone(ctx, api.Zone{ID: zoneID, Name: aName, TTL: aTTL})
+					return err
+				}),
+				Config: testAccZoneResourceConfigCreate(aName, anotherTTL),
+				Check: resource.TestCheckResourceAttr(
+					"hetznerdns_zone.zone1", "ttl", strconv.Itoa(anotherTTL)),
+			},
+			{
+				// The zone was deleted outside of Terraform
+				PreConfig: testAccChangeOutside(t, func(ctx context.Context, client *api.Client) error {
+					return client.DeleteZone(ctx, zoneID)
+				}),
+				Config: testAccZoneResourceConfigCreate(aName, anotherTTL),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet("hetznerdns_zone.zone1", "id"),
+					resource.TestCheckResourceAttr(
+						"hetznerdns_zone.zone1", "name", aName),
 				),
 			},
 		},
@@ -1,15 +1,15 @@
 package main
 
 import (
-	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	plugin "github.com/hashicorp/terraform-plugin-sdk/v2/plugin"
 	"github.com/timohirt/terraform-provider-hetznerdns/hetznerdns"
 )
 
+// version is set to the release version at build time, see .goreleaser.yml
+var version = hetznerdns.DevVersion
+
 func main() {
 	plugin.Serve(&plugin.ServeOpts{
-		ProviderFunc: func() *schema.Provider {
-			return hetznerdns.Provider()
-		},
+		ProviderFunc: hetznerdns.New(version),
 	})
 }